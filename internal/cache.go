@@ -1,25 +1,89 @@
 package promtop
 
+import "slices"
+
 type Data interface {
 	GetCpu(string) map[string]float64
 	GetMemory(string) map[string]float64
+	GetInfo(string) map[string]string // Fields describing the machine, see buildNodeInfo
+	GetLoad(string) map[string]float64
+	GetPressure(string) map[string]float64 // nil if the node doesn't expose PSI
+	GetSwap(string) map[string]float64     // Swap space, paging rates and OOM kills, see buildSwap
+	GetSensors(string) []Sensor
+	GetFrequency(string) CPUFrequency
+	GetKernel(string) map[string]float64
+	GetNetStack(string) NetStack
+	GetNetwork(string) []NetworkInterface
+	GetDiskHealth(string) DiskHealth
+	GetSystemd(string) SystemdUnits
+	GetTimeSync() []NodeClock
+	GetEnergy(string) map[string]float64 // nil if the node has no RAPL counters, empty until rates can be taken
+	GetNFS(string) NFSStats
+	GetTopology(string) CPUTopology
+	GetInterrupts(string) Interrupts
+	GetContainers(string) Containers
 	GetNodes() []string
+	GetNodeLabels() map[string]map[string]string // Target labels keyed by node name
+	GetNodeNames() map[string]string             // node_uname_info nodename keyed by node name
 	Check() error
-	GetType() string // Returns "prometheus" or "node_exporter"
+	GetType() string // Returns "prometheus", "node_exporter" or "cadvisor"
 }
 
 type Cache struct {
 	Data
-	nodes []string
+	nodes  []string
+	stale  bool // Read the node list again on the next GetNodes
+	labels map[string]map[string]string
+	names  map[string]string
 }
 
+// GetNodes returns the nodes of the source
+// When the node set changes the labels and names are read again, so new targets get theirs
 func (c *Cache) GetNodes() []string {
-	if c.nodes == nil {
-		c.nodes = c.Data.GetNodes()
+	if c.nodes == nil || c.stale {
+		nodes := c.Data.GetNodes()
+		if c.nodes != nil && !sameNodes(nodes, c.nodes) {
+			c.labels = nil
+			c.names = nil
+		}
+		c.nodes = nodes
+		c.stale = false
 	}
 	return c.nodes
 }
 
+// sameNodes reports whether two node lists hold the same nodes, in any order
+func sameNodes(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// GetNodeLabels returns the target labels of each node, read when they are first asked for and when the node set changes
+func (c *Cache) GetNodeLabels() map[string]map[string]string {
+	if c.labels == nil {
+		c.labels = c.Data.GetNodeLabels()
+		// Sources without labels return nil, which mustn't mean "not read yet"
+		if c.labels == nil {
+			c.labels = make(map[string]map[string]string)
+		}
+	}
+	return c.labels
+}
+
+// GetNodeNames returns the nodename of each node, resolved when they are first asked for and when the node set changes
+// NewDashboard asks before the UI starts, so node_exporter targets aren't scraped for names on every tick
+func (c *Cache) GetNodeNames() map[string]string {
+	if c.names == nil {
+		c.names = c.Data.GetNodeNames()
+		if c.names == nil {
+			c.names = make(map[string]string)
+		}
+	}
+	return c.names
+}
+
 func (c *Cache) NumberOfNodes() int {
 	return len(c.GetNodes())
 }
@@ -34,6 +98,8 @@ func (c *Cache) MaxNodeNameLen() int {
 	return maxNodeNameLen
 }
 
+// clear makes the next GetNodes read the node list again
+// Labels and names are kept unless the node set turns out to have changed
 func (c *Cache) clear() {
-	c.nodes = nil
+	c.stale = true
 }
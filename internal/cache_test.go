@@ -0,0 +1,49 @@
+package promtop
+
+import "testing"
+
+// countingSource is a source whose targets can change, counting how often labels and names are read
+type countingSource struct {
+	Data
+	nodes      []string
+	labelReads int
+	nameReads  int
+}
+
+func (s *countingSource) GetNodes() []string { return s.nodes }
+
+func (s *countingSource) GetNodeLabels() map[string]map[string]string {
+	s.labelReads++
+	return nil
+}
+
+func (s *countingSource) GetNodeNames() map[string]string {
+	s.nameReads++
+	return nil
+}
+
+func TestCacheRefreshesLabelsAndNames(t *testing.T) {
+	source := &countingSource{nodes: []string{"10.0.0.1:9100", "10.0.0.2:9100"}}
+	cache := Cache{Data: source}
+	refresh := func() {
+		cache.clear()
+		cache.GetNodes()
+		cache.GetNodeLabels()
+		cache.GetNodeNames()
+	}
+
+	refresh()
+	refresh()
+	// The same targets in another order are the same node set
+	source.nodes = []string{"10.0.0.2:9100", "10.0.0.1:9100"}
+	refresh()
+	if source.labelReads != 1 || source.nameReads != 1 {
+		t.Errorf("read labels %d and names %d times for an unchanged node set, want once", source.labelReads, source.nameReads)
+	}
+
+	source.nodes = append(source.nodes, "10.0.0.3:9100")
+	refresh()
+	if source.labelReads != 2 || source.nameReads != 2 {
+		t.Errorf("read labels %d and names %d times after a target appeared, want twice", source.labelReads, source.nameReads)
+	}
+}
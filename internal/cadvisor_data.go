@@ -0,0 +1,48 @@
+package promtop
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// CAdvisorData reads container metrics straight from cAdvisor /metrics endpoints
+// It scrapes and parses the same way as node_exporter, so the node charts are empty and the containers chart is filled
+type CAdvisorData struct {
+	*NodeExporterData
+}
+
+func NewCAdvisorData(urls []*url.URL) (*CAdvisorData, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("at least one cAdvisor URL required")
+	}
+	nd, err := NewNodeExporterData(urls)
+	if err != nil {
+		return nil, err
+	}
+	return &CAdvisorData{NodeExporterData: nd}, nil
+}
+
+// Check succeeds if at least one endpoint exposes cAdvisor container metrics
+func (c *CAdvisorData) Check() error {
+	var lastErr error
+	for _, node := range c.GetNodes() {
+		data, err := c.fetch(node)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to read metrics from %s: %w", node, err)
+			continue
+		}
+		if _, ok := data[containerCheckMetric]; !ok {
+			lastErr = fmt.Errorf("%s does not provide %s metric", node, containerCheckMetric)
+			continue
+		}
+		return nil
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return fmt.Errorf("no cAdvisor endpoints available")
+}
+
+func (c *CAdvisorData) GetType() string {
+	return "cadvisor"
+}
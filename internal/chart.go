@@ -2,8 +2,40 @@ package promtop
 
 // Chart represents a chart displaying metrics for a specific node
 type Chart struct {
-	NodeRef    NodeRef
-	ChartType  string               // "cpu", "memory", "disk", "network"
-	CpuData    map[string][]float64 // CPU name -> time series
-	MemoryData map[string]float64   // Memory metrics: total, available, used, used_percent, cached, buffers
+	NodeRef        NodeRef
+	ChartType      string               // "cpu", "memory", "disk", "network", "info", "load", "pressure", "swap", "sensors", "frequency", "kernel", "netstat", "diskhealth", "systemd", "timesync", "energy", "nfs", "interrupts", "containers"
+	CpuData        map[string][]float64 // CPU name -> time series
+	MemoryData     map[string]float64   // Memory metrics: total, available, used, used_percent, cached, buffers, slab, ...
+	Expanded       bool                 // Show the detailed view where the chart has one
+	InfoData       map[string]string    // Node info: hostname, os, kernel, cpu_model, boot_time, memory_total, ...
+	LoadData       map[string][]float64 // Load metric -> time series: load1, load5, load15, cores, procs_running, ...
+	PressureData   map[string][]float64 // PSI stall % -> time series: cpu_some, memory_some, memory_full, io_some, ...
+	SwapData       map[string][]float64 // Swap and paging -> time series: swap_used, swap_in, page_in, oom_kills, ...
+	SensorData     []Sensor             // Latest temperature and fan readings
+	FrequencyData  *CPUFrequency        // Latest CPU frequencies and throttle rates
+	KernelData     map[string]float64   // Kernel resources and their limits: filefd_allocated, filefd_maximum, ...
+	NetStackData   *NetStack            // Latest TCP/UDP rates and softnet backlog per CPU
+	NetworkData    []NetworkInterface   // Latest state and throughput of each interface
+	DiskHealthData *DiskHealth          // Latest RAID and SMART state
+	SystemdData    *SystemdUnits        // Latest systemd unit states
+	TimeSyncData   []NodeClock          // Latest clock state of every node of the source
+	EnergyData     map[string][]float64 // Watts -> time series: package:0, dram:0, core:0, total, source_total, ...
+	NFSData        *NFSStats            // Latest NFS operation rates, latencies and retransmissions
+	TopologyData   *CPUTopology         // Socket and core of each CPU and NUMA memory, fetched for the detailed CPU view
+	InterruptData  *Interrupts          // Latest interrupt and softirq rates per source and CPU
+	ContainerData  *Containers          // Latest cAdvisor container resource use
+
+	// Nodenames and target labels of the source's nodes, for naming and grouping the charts that list the whole source
+	NodeNames  map[string]string
+	NodeLabels map[string]map[string]string
+}
+
+// appendHistory appends the latest values to their time series, keeping at most maxPoints of each
+func appendHistory(history map[string][]float64, values map[string]float64, maxPoints int) {
+	for name, value := range values {
+		history[name] = append(history[name], value)
+		if len(history[name]) > maxPoints {
+			history[name] = history[name][len(history[name])-maxPoints:]
+		}
+	}
 }
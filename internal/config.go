@@ -0,0 +1,119 @@
+package promtop
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds the user settings shared by the data sources and the UI
+type Config struct {
+	// RateFunction is the function used to turn counters into rates: "rate" or "irate"
+	// rate averages over the whole window, irate only uses the last two samples
+	RateFunction string `mapstructure:"rate"`
+
+	// Theme is the name of a built-in or user-defined theme
+	Theme string `mapstructure:"theme"`
+
+	// Themes are user-defined themes keyed by name
+	Themes map[string]ThemeConfig `mapstructure:"themes"`
+
+	// GroupBy lists the Prometheus target labels used to group nodes, outermost first
+	GroupBy []string `mapstructure:"group_by"`
+
+	// Names set aliases and rewrites for node display names
+	Names NamesConfig `mapstructure:"names"`
+
+	// SystemdUnits are glob patterns, e.g. "nginx*", that narrow the systemd chart to matching units
+	SystemdUnits []string `mapstructure:"systemd_units"`
+
+	// Keys override key bindings, keyed by mode ("dashboard" or "modal") then binding name
+	Keys map[string]map[string][]string `mapstructure:"keys"`
+}
+
+// DefaultConfig returns the settings used when nothing is configured
+func DefaultConfig() Config {
+	return Config{
+		RateFunction: "rate",
+		Theme:        "dark",
+	}
+}
+
+// DefaultConfigPath returns the config file location, following the XDG base directory spec
+func DefaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "promtop", "config.yaml")
+}
+
+// LoadConfig reads the config file on top of the defaults
+// A missing file is only an error if the path was chosen explicitly
+func LoadConfig(v *viper.Viper, path string) (Config, error) {
+	cfg := DefaultConfig()
+	v.SetDefault("rate", cfg.RateFunction)
+	v.SetDefault("theme", cfg.Theme)
+
+	explicit := path != ""
+	if !explicit {
+		path = DefaultConfigPath()
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			if explicit || !errors.Is(err, os.ErrNotExist) {
+				return cfg, fmt.Errorf("failed to read config %s: %w", path, err)
+			}
+		}
+	}
+
+	if err := v.Unmarshal(&cfg); err != nil {
+		return cfg, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the settings for unsupported values
+func (c Config) Validate() error {
+	if c.RateFunction != "rate" && c.RateFunction != "irate" {
+		return fmt.Errorf("rate function must be rate or irate, got: %s", c.RateFunction)
+	}
+	if _, err := resolveTheme(c.Theme, c.Themes); err != nil {
+		return err
+	}
+	if _, err := compileRewrites(c.Names.Rewrites); err != nil {
+		return err
+	}
+	if err := validateUnitPatterns(c.SystemdUnits); err != nil {
+		return err
+	}
+	km := DefaultKeyMap()
+	if err := km.applyKeys(c.Keys); err != nil {
+		return err
+	}
+	return nil
+}
+
+// config is the active configuration, set once at startup
+var config = DefaultConfig()
+
+// SetConfig replaces the active configuration
+func SetConfig(c Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	config = c
+	theme, _ = resolveTheme(c.Theme, c.Themes)
+	nameRewrites, _ = compileRewrites(c.Names.Rewrites)
+	keyMap = DefaultKeyMap()
+	keyMap.applyKeys(c.Keys)
+	return nil
+}
@@ -1,13 +1,13 @@
 package promtop
 
 import (
-	"fmt"
 	"math"
 	"time"
 )
 
 const (
 	// CPU_RATE_INTERVAL is the time window in seconds used to calculate CPU usage rates
+	// Prometheus queries widen it when the scrape interval is too long to fill it
 	CPU_RATE_INTERVAL = 60
 
 	// UPDATE_INTERVAL is the time between data updates in seconds
@@ -24,8 +24,3 @@ func MaxCPURecords() int {
 func UpdateDuration() time.Duration {
 	return time.Duration(UPDATE_INTERVAL) * time.Second
 }
-
-// CPURateIntervalString returns the CPU rate interval formatted for Prometheus queries (e.g., "60s")
-func CPURateIntervalString() string {
-	return fmt.Sprintf("%ds", CPU_RATE_INTERVAL)
-}
@@ -0,0 +1,172 @@
+package promtop
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Containers is the resource use of the containers on a node, as reported by cAdvisor
+type Containers struct {
+	Cores      float64     // CPU cores of the machine, 0 if not reported
+	Containers []Container // Busiest CPU first
+}
+
+// Container is the resource use of a container, rates are per second
+type Container struct {
+	Name        string
+	Image       string
+	CPU         float64 // Cores in use
+	Memory      float64 // Working set bytes
+	MemoryLimit float64 // Bytes, 0 if unlimited
+	Receive     float64 // Network bytes
+	Transmit    float64 // Network bytes
+	FSRead      float64 // Filesystem bytes
+	FSWrite     float64 // Filesystem bytes
+	FSUsage     float64 // Filesystem bytes used
+}
+
+// containerGauges are the cAdvisor gauges behind the containers chart
+var containerGauges = []string{
+	"container_memory_working_set_bytes",
+	"container_spec_memory_limit_bytes",
+	"container_fs_usage_bytes",
+	"machine_cpu_cores",
+}
+
+// containerCounters are the cAdvisor counters behind the containers chart
+var containerCounters = []string{
+	"container_cpu_usage_seconds_total",
+	"container_network_receive_bytes_total",
+	"container_network_transmit_bytes_total",
+	"container_fs_reads_bytes_total",
+	"container_fs_writes_bytes_total",
+}
+
+// containerCheckMetric is the metric that identifies a cAdvisor endpoint
+const containerCheckMetric = "container_cpu_usage_seconds_total"
+
+// Working set that needs attention, as a percentage of the container's memory limit
+const (
+	containerMemoryWarningPercent  = 80.0
+	containerMemoryCriticalPercent = 95.0
+)
+
+// containerName names the container a cAdvisor series belongs to: namespace/pod/container on Kubernetes, or its Docker name
+// The kubelet's cAdvisor sets name to the runtime's container ID, so it is only used for series without a pod
+// Series for cgroups that aren't a container, and the Kubernetes pause container, give ""
+func containerName(labels map[string]string) string {
+	if pod := labels["pod"]; pod != "" {
+		if container := labels["container"]; container != "" && container != "POD" {
+			return labels["namespace"] + "/" + pod + "/" + container
+		}
+		return ""
+	}
+	return labels["name"]
+}
+
+// buildContainers joins the cAdvisor gauges and counter rates, both keyed by metric name, into one entry per container
+func buildContainers(gauges, rates map[string][]sample) Containers {
+	var containers Containers
+	for _, s := range gauges["machine_cpu_cores"] {
+		containers.Cores = s.value
+	}
+
+	byName := make(map[string]*Container)
+	each := func(series []sample, set func(c *Container, value float64)) {
+		for _, s := range series {
+			name := containerName(s.labels)
+			if name == "" {
+				continue
+			}
+			if byName[name] == nil {
+				byName[name] = &Container{Name: name}
+			}
+			if image := s.labels["image"]; image != "" {
+				byName[name].Image = image
+			}
+			set(byName[name], s.value)
+		}
+	}
+
+	// Series are split by CPU, interface and device, so values are summed per container
+	each(gauges["container_memory_working_set_bytes"], func(c *Container, v float64) { c.Memory += v })
+	each(gauges["container_spec_memory_limit_bytes"], func(c *Container, v float64) { c.MemoryLimit = max(c.MemoryLimit, v) })
+	each(gauges["container_fs_usage_bytes"], func(c *Container, v float64) { c.FSUsage += v })
+	each(rates["container_cpu_usage_seconds_total"], func(c *Container, v float64) { c.CPU += v })
+	each(rates["container_network_receive_bytes_total"], func(c *Container, v float64) { c.Receive += v })
+	each(rates["container_network_transmit_bytes_total"], func(c *Container, v float64) { c.Transmit += v })
+	each(rates["container_fs_reads_bytes_total"], func(c *Container, v float64) { c.FSRead += v })
+	each(rates["container_fs_writes_bytes_total"], func(c *Container, v float64) { c.FSWrite += v })
+
+	for _, c := range byName {
+		containers.Containers = append(containers.Containers, *c)
+	}
+	sort.Slice(containers.Containers, func(i, j int) bool {
+		a, b := containers.Containers[i], containers.Containers[j]
+		if a.CPU != b.CPU {
+			return a.CPU > b.CPU
+		}
+		if a.Memory != b.Memory {
+			return a.Memory > b.Memory
+		}
+		return a.Name < b.Name
+	})
+	return containers
+}
+
+// renderContainers renders the containers chart: the top containers by CPU with their memory, network and filesystem use
+func renderContainers(chart Chart, height int) string {
+	if chart.ContainerData == nil {
+		return "Waiting for data..."
+	}
+	data := *chart.ContainerData
+	if len(data.Containers) == 0 {
+		return "No cAdvisor container metrics available for this node"
+	}
+
+	// Keep to the busiest containers that fit, leaving room for the summary and the table borders
+	top := data.Containers[:min(len(data.Containers), max(height-5, 1))]
+
+	rows := [][]string{}
+	total := 0.0
+	for _, c := range data.Containers {
+		total += c.CPU
+	}
+	for _, c := range top {
+		cpu := fmt.Sprintf("%.2f", c.CPU)
+		if data.Cores > 0 {
+			cpu += fmt.Sprintf(" (%.1f%%)", c.CPU/data.Cores*100)
+		}
+		memory := formatBytes(c.Memory)
+		if c.MemoryLimit > 0 {
+			percent := c.Memory / c.MemoryLimit * 100
+			memory = statusFor(percent, containerMemoryWarningPercent, containerMemoryCriticalPercent).
+				render(fmt.Sprintf("%s / %s (%.0f%%)", formatBytes(c.Memory), formatBytes(c.MemoryLimit), percent))
+		}
+		rows = append(rows, []string{
+			c.Name,
+			cpu,
+			memory,
+			formatBytes(c.Receive) + "/s",
+			formatBytes(c.Transmit) + "/s",
+			formatBytes(c.FSRead) + "/s",
+			formatBytes(c.FSWrite) + "/s",
+			formatBytes(c.FSUsage),
+		})
+	}
+
+	summary := fmt.Sprintf("%d containers using %.2f cores", len(data.Containers), total)
+	if data.Cores > 0 {
+		summary += fmt.Sprintf(" of %.0f", data.Cores)
+	}
+	if len(top) < len(data.Containers) {
+		summary += fmt.Sprintf(", busiest %d shown", len(top))
+	}
+
+	t := NewWrapTable().
+		MaxHeight(height-1).
+		Headers("Container", "CPU cores", "Memory", "RX", "TX", "FS read", "FS write", "FS used").
+		Rows(rows...)
+
+	return summary + "\n" + t.Render()
+}
@@ -0,0 +1,108 @@
+package promtop
+
+import "testing"
+
+func TestBuildContainers(t *testing.T) {
+	redis := func(value float64, labels ...string) sample {
+		s := sample{labels: map[string]string{"id": "/docker/3f2a9b8c", "image": "redis:7", "name": "redis"}, value: value}
+		for i := 0; i+1 < len(labels); i += 2 {
+			s.labels[labels[i]] = labels[i+1]
+		}
+		return s
+	}
+	web := sample{labels: map[string]string{"id": "/docker/8c3e0f6a", "image": "nginx:1.25", "name": "web"}, value: 0.25}
+	root := sample{labels: map[string]string{"id": "/"}, value: 3.2e9}
+
+	containers := buildContainers(map[string][]sample{
+		"machine_cpu_cores":                  {{labels: map[string]string{}, value: 8}},
+		"container_memory_working_set_bytes": {root, redis(8 << 20)},
+		"container_spec_memory_limit_bytes":  {redis(16 << 20)},
+	}, map[string][]sample{
+		// Split by CPU, interface and device, so summed per container
+		"container_cpu_usage_seconds_total": {
+			redis(0.125, "cpu", "cpu00"),
+			redis(0.375, "cpu", "cpu01"),
+			web,
+		},
+		"container_network_receive_bytes_total":  {redis(2048, "interface", "eth0")},
+		"container_network_transmit_bytes_total": {redis(1024, "interface", "eth0")},
+		"container_fs_reads_bytes_total":         {redis(512, "device", "/dev/sda")},
+		"container_fs_writes_bytes_total":        {redis(256, "device", "/dev/sda")},
+	})
+
+	if containers.Cores != 8 || len(containers.Containers) != 2 {
+		t.Fatalf("buildContainers() = %+v, want 8 cores and two containers", containers)
+	}
+	// Busiest CPU first, the root cgroup isn't a container
+	got := containers.Containers[0]
+	want := Container{Name: "redis", Image: "redis:7", CPU: 0.5, Memory: 8 << 20, MemoryLimit: 16 << 20, Receive: 2048, Transmit: 1024, FSRead: 512, FSWrite: 256}
+	if got != want {
+		t.Errorf("busiest container = %+v, want %+v", got, want)
+	}
+	if containers.Containers[1].Name != "web" {
+		t.Errorf("second container = %q, want web", containers.Containers[1].Name)
+	}
+}
+
+func TestContainerName(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{
+			name: "kubelet container",
+			labels: map[string]string{
+				"container": "nginx",
+				"id":        "/kubepods/burstable/pod5f1d2c9e/8c3e0f6a1b2d",
+				"image":     "docker.io/library/nginx:1.25",
+				"name":      "8c3e0f6a1b2d4e5f9a7b6c5d4e3f2a1b",
+				"namespace": "web",
+				"pod":       "nginx-7c5d8bf9f7-2x8kq",
+			},
+			want: "web/nginx-7c5d8bf9f7-2x8kq/nginx",
+		},
+		{
+			name: "kubelet pause container",
+			labels: map[string]string{
+				"container": "POD",
+				"id":        "/kubepods/burstable/pod5f1d2c9e/2b7e4f1a9c3d",
+				"image":     "registry.k8s.io/pause:3.9",
+				"name":      "2b7e4f1a9c3d8e6f5a4b3c2d1e0f9a8b",
+				"namespace": "web",
+				"pod":       "nginx-7c5d8bf9f7-2x8kq",
+			},
+			want: "",
+		},
+		{
+			name: "kubelet pod cgroup",
+			labels: map[string]string{
+				"id":        "/kubepods/burstable/pod5f1d2c9e",
+				"namespace": "web",
+				"pod":       "nginx-7c5d8bf9f7-2x8kq",
+			},
+			want: "",
+		},
+		{
+			name: "docker container",
+			labels: map[string]string{
+				"id":    "/docker/3f2a9b8c7d6e5f4a3b2c1d0e9f8a7b6c",
+				"image": "redis:7",
+				"name":  "redis",
+			},
+			want: "redis",
+		},
+		{
+			name:   "root cgroup",
+			labels: map[string]string{"id": "/"},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containerName(tt.labels); got != tt.want {
+				t.Errorf("containerName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package promtop
+
+import (
+	"strings"
+	"time"
+)
+
+// counterSample is a single reading of a counter
+type counterSample struct {
+	timestamp time.Time
+	value     float64
+}
+
+// counterStore keeps a sliding window of readings for each counter series
+// so rates can be calculated the same way Prometheus rate() and irate() do
+type counterStore struct {
+	series map[string][]counterSample
+}
+
+// newCounterStore creates an empty counter store
+func newCounterStore() *counterStore {
+	return &counterStore{
+		series: make(map[string][]counterSample),
+	}
+}
+
+// add appends a reading for a series, keeping at most MaxCPURecords readings
+func (c *counterStore) add(key string, timestamp time.Time, value float64) {
+	samples := append(c.series[key], counterSample{timestamp: timestamp, value: value})
+	if maxRecords := MaxCPURecords(); len(samples) > maxRecords {
+		samples = samples[len(samples)-maxRecords:]
+	}
+	c.series[key] = samples
+}
+
+// rate returns the per-second increase of a series using the configured rate function
+// Returns false until the series has at least two readings
+func (c *counterStore) rate(key string) (float64, bool) {
+	samples := c.series[key]
+	if len(samples) < 2 {
+		return 0, false
+	}
+
+	// irate only looks at the last two readings
+	if config.RateFunction == "irate" {
+		samples = samples[len(samples)-2:]
+	}
+
+	// sum the increases between readings, a drop means the counter was reset
+	// so the new value is the increase since the reset
+	increase := 0.0
+	for i := 1; i < len(samples); i++ {
+		if samples[i].value >= samples[i-1].value {
+			increase += samples[i].value - samples[i-1].value
+		} else {
+			increase += samples[i].value
+		}
+	}
+
+	interval := samples[len(samples)-1].timestamp.Sub(samples[0].timestamp).Seconds()
+	if interval <= 0 {
+		return 0, false
+	}
+	return increase / interval, true
+}
+
+// delta returns the per-second change of a gauge series between its first and last readings
+// Unlike rate a drop is a decrease, not a reset, the same as Prometheus deriv()
+func (c *counterStore) delta(key string) (float64, bool) {
+	samples := c.series[key]
+	if len(samples) < 2 {
+		return 0, false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	interval := last.timestamp.Sub(first.timestamp).Seconds()
+	if interval <= 0 {
+		return 0, false
+	}
+	return (last.value - first.value) / interval, true
+}
+
+// prune forgets the series of a metric that are not in seen, so series that disappear don't pile up
+// The keys of a metric's series are the metric name followed by their labels, see seriesKey
+func (c *counterStore) prune(metric string, seen map[string]bool) {
+	for key := range c.series {
+		if strings.HasPrefix(key, metric+"{") && !seen[key] {
+			delete(c.series, key)
+		}
+	}
+}
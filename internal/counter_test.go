@@ -0,0 +1,84 @@
+package promtop
+
+import (
+	"testing"
+	"time"
+)
+
+// readings returns a store holding the values of one series, read a second apart
+func readings(values ...float64) *counterStore {
+	c := newCounterStore()
+	start := time.Unix(1700000000, 0)
+	for i, value := range values {
+		c.add("series", start.Add(time.Duration(i)*time.Second), value)
+	}
+	return c
+}
+
+func TestCounterStoreRate(t *testing.T) {
+	previous := config.RateFunction
+	t.Cleanup(func() { config.RateFunction = previous })
+
+	tests := []struct {
+		name     string
+		function string
+		values   []float64
+		want     float64
+		ok       bool
+	}{
+		{name: "one reading", function: "rate", values: []float64{100}},
+		{name: "rate averages the window", function: "rate", values: []float64{100, 110, 130}, want: 15, ok: true},
+		{name: "irate takes the last two", function: "irate", values: []float64{100, 110, 130}, want: 20, ok: true},
+		{name: "counter reset", function: "rate", values: []float64{100, 110, 4}, want: 7, ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.RateFunction = tt.function
+			if got, ok := readings(tt.values...).rate("series"); got != tt.want || ok != tt.ok {
+				t.Errorf("rate() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCounterStoreDelta(t *testing.T) {
+	// A gauge going down is a decrease, where rate would see a counter reset
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+		ok     bool
+	}{
+		{name: "one reading", values: []float64{100}},
+		{name: "growing", values: []float64{100, 110, 130}, want: 15, ok: true},
+		{name: "shrinking", values: []float64{130, 110, 100}, want: -15, ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := readings(tt.values...).delta("series"); got != tt.want || ok != tt.ok {
+				t.Errorf("delta() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCounterStorePrune(t *testing.T) {
+	c := newCounterStore()
+	now := time.Unix(1700000000, 0)
+	for _, key := range []string{`container_cpu_usage_seconds_total{id="/a"}`, `container_cpu_usage_seconds_total{id="/b"}`, `container_cpu_usage_seconds_total_extra{id="/b"}`, "cpu:0"} {
+		c.add(key, now, 1)
+	}
+
+	// /b is gone from the scrape, other metrics are left alone
+	c.prune("container_cpu_usage_seconds_total", map[string]bool{`container_cpu_usage_seconds_total{id="/a"}`: true})
+	for key, kept := range map[string]bool{
+		`container_cpu_usage_seconds_total{id="/a"}`:       true,
+		`container_cpu_usage_seconds_total{id="/b"}`:       false,
+		`container_cpu_usage_seconds_total_extra{id="/b"}`: true,
+		"cpu:0": true,
+	} {
+		if _, ok := c.series[key]; ok != kept {
+			t.Errorf("prune() kept %s = %v, want %v", key, ok, kept)
+		}
+	}
+}
@@ -1,22 +1,40 @@
 package promtop
 
 import (
+	"fmt"
 	"log"
+	"slices"
 	"sort"
-	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/lipgloss/tree"
 )
 
 type NodeRef struct {
-	Type        string // prometheus, prometheus_node, node_exporter
-	SourceIndex int    // Index into sources array
-	SourceName  string // Human-readable source name (hostname from URL)
-	NodeName    string // Node name from GetNodes() (empty if IsSourceHeader)
-	DisplayName string // Formatted for UI
+	Type        string            // prometheus, group, prometheus_node, node_exporter, cadvisor
+	SourceIndex int               // Index into sources array
+	SourceName  string            // Human-readable source name (hostname from URL)
+	NodeName    string            // Node name from GetNodes() (empty for headers and groups)
+	DisplayName string            // Formatted for UI
+	Labels      map[string]string // Target labels (Prometheus nodes only)
+	Depth       int               // Nesting level in the node tree (0 = top level)
+	GroupKey    string            // Identifies a source header or group for collapsing
+	NodeCount   int               // Number of nodes under a source header or group
+}
+
+// IsNode reports whether the ref is a node rather than a source header or group
+func (r NodeRef) IsNode() bool {
+	return r.Type == "prometheus_node" || r.Type == "node_exporter" || r.Type == "cadvisor"
+}
+
+// offersChart reports whether a chart of the given type can be added for the ref
+// cAdvisor only reports containers, so its nodes only offer the containers chart
+func (r NodeRef) offersChart(chartType string) bool {
+	return r.Type != "cadvisor" || chartType == "containers"
 }
 
 type dashboardModel struct {
@@ -28,10 +46,16 @@ type dashboardModel struct {
 	selectedTab    int
 	tabs           []string
 	cpuData        [][]float64
-	activePanes    []*TabSet // Each pane can contain multiple tabbed charts
-	showModal      bool      // true = modal open, false = modal closed
-	modalNewPane   bool      // true = create new pane, false = add to current pane
-	modalChartType string    // Chart type being added in modal
+	activePanes    []*TabSet       // Each pane can contain multiple tabbed charts
+	showModal      bool            // true = modal open, false = modal closed
+	showHelp       bool            // true = key binding overlay open
+	modalNewPane   bool            // true = create new pane, false = add to current pane
+	modalChartType string          // Chart type being added in modal
+	nodeOffset     int             // First node list row shown in the modal
+	collapsed      map[string]bool // Collapsed sources and groups in the modal, by GroupKey
+	search         textinput.Model // Node filter typed in the modal
+	searching      bool            // true = keys go to the search box
+	status         string          // Shown in place of the help bar until the next key press
 	width          int
 	height         int
 	ready          bool
@@ -55,6 +79,8 @@ func NewDashboard(sources []Cache, sourceNames []string) *dashboardModel {
 		tabs:         []string{"CPU", "Memory", "Disk", "Network"},
 		cpuData:      make([][]float64, 0),
 		activePanes:  make([]*TabSet, 0),
+		search:       newSearchInput(),
+		collapsed:    make(map[string]bool),
 	}
 	m.nodeRefs = m.refreshNodes()
 	return m
@@ -63,39 +89,78 @@ func NewDashboard(sources []Cache, sourceNames []string) *dashboardModel {
 func (m dashboardModel) refreshNodes() []NodeRef {
 	var nodeRefs []NodeRef
 
-	// Iterate through each source
-	for sourceIdx, source := range m.sources {
+	// Iterate through each source by pointer, so the labels and names the Cache read are kept for the next refresh
+	for sourceIdx := range m.sources {
+		source := &m.sources[sourceIdx]
+		// Targets come and go, so the node list is read again each time
+		source.clear()
 		nodes := source.GetNodes()
-		sort.Strings(nodes)
+		labels := source.GetNodeLabels()
+		names := source.GetNodeNames()
+
+		// Sort by the name the user sees rather than the address
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return nodeDisplayName(nodes[i], names[nodes[i]]) < nodeDisplayName(nodes[j], names[nodes[j]])
+		})
 
 		if source.GetType() == "prometheus" {
-			// For Prometheus: add source header, then nodes
+			// For Prometheus: add source header, then groups, then nodes
+			sourceName := m.sourceNames[sourceIdx]
 			nodeRefs = append(nodeRefs, NodeRef{
 				Type:        "prometheus",
 				SourceIndex: sourceIdx,
-				SourceName:  m.sourceNames[sourceIdx],
+				SourceName:  sourceName,
 				NodeName:    "",
-				DisplayName: m.sourceNames[sourceIdx],
+				DisplayName: sourceName,
+				GroupKey:    sourceName,
+				NodeCount:   len(nodes),
 			})
 
-			for _, nodeName := range nodes {
-				nodeRefs = append(nodeRefs, NodeRef{
-					Type:        "prometheus_node",
-					SourceIndex: sourceIdx,
-					SourceName:  m.sourceNames[sourceIdx],
-					NodeName:    nodeName,
-					DisplayName: nodeName,
-				})
+			groupBy := config.GroupBy
+			groups := groupNodes(nodes, labels, groupBy)
+			counts := groupCounts(sourceName, groupBy, groups)
+			var previous []string
+			for _, group := range groups {
+				// Add a group row for each level that differs from the previous group
+				for level, value := range group.values {
+					if previous != nil && slices.Equal(previous[:level+1], group.values[:level+1]) {
+						continue
+					}
+					key := groupKey(sourceName, groupBy, group.values[:level+1])
+					nodeRefs = append(nodeRefs, NodeRef{
+						Type:        "group",
+						SourceIndex: sourceIdx,
+						SourceName:  sourceName,
+						DisplayName: groupBy[level] + "=" + value,
+						Depth:       level + 1,
+						GroupKey:    key,
+						NodeCount:   counts[key],
+					})
+				}
+				previous = group.values
+
+				for _, nodeName := range group.nodes {
+					nodeRefs = append(nodeRefs, NodeRef{
+						Type:        "prometheus_node",
+						SourceIndex: sourceIdx,
+						SourceName:  sourceName,
+						NodeName:    nodeName,
+						DisplayName: nodeDisplayName(nodeName, names[nodeName]),
+						Labels:      labels[nodeName],
+						Depth:       len(groupBy) + 1,
+					})
+				}
 			}
 		} else {
-			// For node_exporter: single line per node (no header)
+			// For node_exporter and cAdvisor: single line per node (no header)
 			for _, nodeName := range nodes {
 				nodeRefs = append(nodeRefs, NodeRef{
-					Type:        "node_exporter",
+					Type:        source.GetType(),
 					SourceIndex: sourceIdx,
 					SourceName:  m.sourceNames[sourceIdx],
 					NodeName:    nodeName,
-					DisplayName: nodeName,
+					DisplayName: nodeDisplayName(nodeName, names[nodeName]),
+					Labels:      labels[nodeName],
 				})
 			}
 		}
@@ -111,156 +176,28 @@ func (m dashboardModel) Init() tea.Cmd {
 func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			return m, tea.Quit
-		case "esc":
-			// Close modal if open
-			if m.showModal {
-				m.showModal = false
-				m.modalNewPane = false
-				m.modalChartType = ""
-			}
-		case "n":
-			if m.showModal {
-				// Add Network chart for selected node (from modal)
-				m = m.addChart("network")
-				m.showModal = false
-				m.modalNewPane = false
-			} else if len(m.activePanes) < 9 {
-				// Open modal to add new pane
-				m.showModal = true
-				m.modalNewPane = true
-			}
-		case "a":
-			if m.showModal {
-				// Add all chart types for selected node (from modal)
-				m = m.addChart("cpu")
-				m = m.addChart("memory")
-				m = m.addChart("disk")
-				m = m.addChart("network")
-				m.showModal = false
-				m.modalNewPane = false
-			} else {
-				// Open modal to add charts to current pane
-				m.showModal = true
-				m.modalNewPane = false
+		m.status = ""
+		// The help overlay takes over the keyboard until it is closed
+		if m.showHelp {
+			if key.Matches(msg, keyMap.Dashboard.Quit) {
+				return m, tea.Quit
 			}
-		case "j", "down":
-			if m.showModal {
-				// Navigate nodes in modal
-				if m.selectedNode < len(m.nodeRefs)-1 {
-					m.selectedNode++
-				}
-			} else {
-				// Navigate panes in grid
-				columns := m.getGridColumns()
-				if m.selectedPane+columns < len(m.activePanes) {
-					m.selectedPane += columns
-				}
-			}
-		case "k", "up":
-			if m.showModal {
-				// Navigate nodes in modal
-				if m.selectedNode > 0 {
-					m.selectedNode--
-				}
-			} else {
-				// Navigate panes in grid
-				columns := m.getGridColumns()
-				if m.selectedPane-columns >= 0 {
-					m.selectedPane -= columns
-				}
-			}
-		case "h", "left":
-			if !m.showModal {
-				// Navigate panes in grid
-				if m.selectedPane > 0 {
-					m.selectedPane--
-				}
-			}
-		case "l", "right":
-			if !m.showModal {
-				// Navigate panes in grid
-				if m.selectedPane < len(m.activePanes)-1 {
-					m.selectedPane++
-				}
-			}
-		case "[":
-			// Previous tab in current pane
-			if !m.showModal && len(m.activePanes) > 0 && m.selectedPane < len(m.activePanes) {
-				m.activePanes[m.selectedPane].PrevTab()
-			}
-		case "]":
-			// Next tab in current pane
-			if !m.showModal && len(m.activePanes) > 0 && m.selectedPane < len(m.activePanes) {
-				m.activePanes[m.selectedPane].NextTab()
-			}
-		case "g":
-			if m.showModal {
-				m.selectedNode = 0
-			} else {
-				m.selectedPane = 0
-			}
-		case "G":
-			if m.showModal {
-				m.selectedNode = len(m.nodeRefs) - 1
-			} else {
-				m.selectedPane = len(m.activePanes) - 1
-			}
-		case "ctrl+d":
-			if m.showModal {
-				m.selectedNode = min(m.selectedNode+5, len(m.nodeRefs)-1)
-			}
-		case "ctrl+u":
-			if m.showModal {
-				m.selectedNode = max(m.selectedNode-5, 0)
-			}
-		case "c":
-			// Add CPU chart for selected node (from modal)
-			if m.showModal {
-				m = m.addChart("cpu")
-				m.showModal = false
-				m.modalNewPane = false
-			}
-		case "m":
-			// Add Memory chart for selected node (from modal)
-			if m.showModal {
-				m = m.addChart("memory")
-				m.showModal = false
-				m.modalNewPane = false
-			}
-		case "s":
-			// Add Storage/Disk chart for selected node (from modal)
-			if m.showModal {
-				m = m.addChart("disk")
-				m.showModal = false
-				m.modalNewPane = false
-			}
-		case "x":
-			// Remove current tab, or pane if only one tab left
-			if !m.showModal && len(m.activePanes) > 0 && m.selectedPane < len(m.activePanes) {
-				selectedPane := m.activePanes[m.selectedPane]
-				charts := selectedPane.GetCharts()
-
-				if len(charts) > 1 {
-					// Remove current tab from pane
-					selectedPane.RemoveCurrentTab()
-				} else {
-					// Only one tab left, remove entire pane
-					m.activePanes = append(m.activePanes[:m.selectedPane], m.activePanes[m.selectedPane+1:]...)
-					// Bounds check selectedPane
-					if m.selectedPane >= len(m.activePanes) {
-						m.selectedPane = max(0, len(m.activePanes)-1)
-					}
-				}
+			if key.Matches(msg, keyMap.Dashboard.Help, keyMap.Modal.Help, keyMap.Modal.Cancel) {
+				m.showHelp = false
 			}
+			return m, nil
 		}
+		if m.showModal {
+			updated, cmd := m.updateModal(msg)
+			return updated.scrollToSelection(), cmd
+		}
+		return m.updateDashboard(msg)
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.ready = true
+		m = m.scrollToSelection()
 
 	case tickMsg:
 		// Update nodes
@@ -270,6 +207,7 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.selectedNode >= len(m.nodeRefs) {
 			m.selectedNode = max(0, len(m.nodeRefs)-1)
 		}
+		m = m.keepSelectionVisible().scrollToSelection()
 
 		// Update CPU and Memory data for all charts in all panes
 		maxDataPoints := max(m.width-40, 20)
@@ -286,15 +224,91 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 
 					// Append new data and trim for each CPU
-					for cpuName, value := range cpus {
-						chart.CpuData[cpuName] = append(chart.CpuData[cpuName], value)
-						if len(chart.CpuData[cpuName]) > maxDataPoints {
-							chart.CpuData[cpuName] = chart.CpuData[cpuName][len(chart.CpuData[cpuName])-maxDataPoints:]
-						}
+					appendHistory(chart.CpuData, cpus, maxDataPoints)
+
+					if chart.Expanded {
+						topology := m.sources[chart.NodeRef.SourceIndex].GetTopology(chart.NodeRef.NodeName)
+						chart.TopologyData = &topology
 					}
 				} else if chart.ChartType == "memory" {
 					// Fetch latest memory data
 					chart.MemoryData = m.sources[chart.NodeRef.SourceIndex].GetMemory(chart.NodeRef.NodeName)
+				} else if chart.ChartType == "info" {
+					chart.InfoData = m.sources[chart.NodeRef.SourceIndex].GetInfo(chart.NodeRef.NodeName)
+				} else if chart.ChartType == "load" {
+					if chart.LoadData == nil {
+						chart.LoadData = make(map[string][]float64)
+					}
+					load := m.sources[chart.NodeRef.SourceIndex].GetLoad(chart.NodeRef.NodeName)
+					appendHistory(chart.LoadData, load, maxDataPoints)
+				} else if chart.ChartType == "pressure" {
+					pressure := m.sources[chart.NodeRef.SourceIndex].GetPressure(chart.NodeRef.NodeName)
+					if pressure == nil {
+						// PSI isn't exposed, an empty history shows as not available
+						chart.PressureData = make(map[string][]float64)
+					} else if len(pressure) > 0 {
+						if chart.PressureData == nil {
+							chart.PressureData = make(map[string][]float64)
+						}
+						appendHistory(chart.PressureData, pressure, maxDataPoints)
+					}
+				} else if chart.ChartType == "swap" {
+					if chart.SwapData == nil {
+						chart.SwapData = make(map[string][]float64)
+					}
+					swap := m.sources[chart.NodeRef.SourceIndex].GetSwap(chart.NodeRef.NodeName)
+					appendHistory(chart.SwapData, swap, maxDataPoints)
+				} else if chart.ChartType == "sensors" {
+					// An empty list shows the node has no sensors
+					chart.SensorData = m.sources[chart.NodeRef.SourceIndex].GetSensors(chart.NodeRef.NodeName)
+					if chart.SensorData == nil {
+						chart.SensorData = []Sensor{}
+					}
+				} else if chart.ChartType == "frequency" {
+					freq := m.sources[chart.NodeRef.SourceIndex].GetFrequency(chart.NodeRef.NodeName)
+					chart.FrequencyData = &freq
+				} else if chart.ChartType == "kernel" {
+					chart.KernelData = m.sources[chart.NodeRef.SourceIndex].GetKernel(chart.NodeRef.NodeName)
+				} else if chart.ChartType == "netstat" {
+					stack := m.sources[chart.NodeRef.SourceIndex].GetNetStack(chart.NodeRef.NodeName)
+					chart.NetStackData = &stack
+				} else if chart.ChartType == "network" {
+					// An empty list shows the node has no interfaces
+					chart.NetworkData = m.sources[chart.NodeRef.SourceIndex].GetNetwork(chart.NodeRef.NodeName)
+					if chart.NetworkData == nil {
+						chart.NetworkData = []NetworkInterface{}
+					}
+				} else if chart.ChartType == "diskhealth" {
+					health := m.sources[chart.NodeRef.SourceIndex].GetDiskHealth(chart.NodeRef.NodeName)
+					chart.DiskHealthData = &health
+				} else if chart.ChartType == "systemd" {
+					source := &m.sources[chart.NodeRef.SourceIndex]
+					units := source.GetSystemd(chart.NodeRef.NodeName)
+					chart.SystemdData = &units
+					chart.NodeNames, chart.NodeLabels = source.GetNodeNames(), source.GetNodeLabels()
+				} else if chart.ChartType == "timesync" {
+					source := &m.sources[chart.NodeRef.SourceIndex]
+					chart.TimeSyncData = source.GetTimeSync()
+					if chart.TimeSyncData == nil {
+						chart.TimeSyncData = []NodeClock{}
+					}
+					chart.NodeNames, chart.NodeLabels = source.GetNodeNames(), source.GetNodeLabels()
+				} else if chart.ChartType == "energy" {
+					// EnergyData stays nil while waiting for rates, and is left empty when the node has no RAPL counters
+					energy := m.sources[chart.NodeRef.SourceIndex].GetEnergy(chart.NodeRef.NodeName)
+					if chart.EnergyData == nil && (energy == nil || len(energy) > 0) {
+						chart.EnergyData = make(map[string][]float64)
+					}
+					appendHistory(chart.EnergyData, energy, maxDataPoints)
+				} else if chart.ChartType == "nfs" {
+					nfs := m.sources[chart.NodeRef.SourceIndex].GetNFS(chart.NodeRef.NodeName)
+					chart.NFSData = &nfs
+				} else if chart.ChartType == "interrupts" {
+					interrupts := m.sources[chart.NodeRef.SourceIndex].GetInterrupts(chart.NodeRef.NodeName)
+					chart.InterruptData = &interrupts
+				} else if chart.ChartType == "containers" {
+					containers := m.sources[chart.NodeRef.SourceIndex].GetContainers(chart.NodeRef.NodeName)
+					chart.ContainerData = &containers
 				}
 			}
 		}
@@ -305,6 +319,212 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateDashboard handles key presses while browsing panes
+func (m dashboardModel) updateDashboard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := keyMap.Dashboard
+	switch {
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, keys.Help):
+		m.showHelp = true
+	case key.Matches(msg, keys.NewPane):
+		if len(m.activePanes) < 9 {
+			// Open modal to add new pane
+			m.showModal = true
+			m.modalNewPane = true
+		}
+	case key.Matches(msg, keys.AddToPane):
+		// Open modal to add charts to current pane
+		m.showModal = true
+		m.modalNewPane = false
+	case key.Matches(msg, keys.Down):
+		// Navigate panes in grid
+		columns := m.getGridColumns()
+		if m.selectedPane+columns < len(m.activePanes) {
+			m.selectedPane += columns
+		}
+	case key.Matches(msg, keys.Up):
+		columns := m.getGridColumns()
+		if m.selectedPane-columns >= 0 {
+			m.selectedPane -= columns
+		}
+	case key.Matches(msg, keys.Left):
+		if m.selectedPane > 0 {
+			m.selectedPane--
+		}
+	case key.Matches(msg, keys.Right):
+		if m.selectedPane < len(m.activePanes)-1 {
+			m.selectedPane++
+		}
+	case key.Matches(msg, keys.PrevTab):
+		if len(m.activePanes) > 0 && m.selectedPane < len(m.activePanes) {
+			m.activePanes[m.selectedPane].PrevTab()
+		}
+	case key.Matches(msg, keys.NextTab):
+		if len(m.activePanes) > 0 && m.selectedPane < len(m.activePanes) {
+			m.activePanes[m.selectedPane].NextTab()
+		}
+	case key.Matches(msg, keys.First):
+		m.selectedPane = 0
+	case key.Matches(msg, keys.Last):
+		m.selectedPane = max(0, len(m.activePanes)-1)
+	case key.Matches(msg, keys.Expand):
+		if m.selectedPane < len(m.activePanes) {
+			m.activePanes[m.selectedPane].ToggleExpanded()
+		}
+	case key.Matches(msg, keys.Remove):
+		// Remove current tab, or pane if only one tab left
+		if len(m.activePanes) > 0 && m.selectedPane < len(m.activePanes) {
+			selectedPane := m.activePanes[m.selectedPane]
+			charts := selectedPane.GetCharts()
+
+			if len(charts) > 1 {
+				// Remove current tab from pane
+				selectedPane.RemoveCurrentTab()
+			} else {
+				// Only one tab left, remove entire pane
+				m.activePanes = append(m.activePanes[:m.selectedPane], m.activePanes[m.selectedPane+1:]...)
+				// Bounds check selectedPane
+				if m.selectedPane >= len(m.activePanes) {
+					m.selectedPane = max(0, len(m.activePanes)-1)
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+// updateModal handles key presses in the node selection modal
+func (m dashboardModel) updateModal(msg tea.KeyMsg) (dashboardModel, tea.Cmd) {
+	keys := m.modalKeys()
+	if m.searching {
+		return m.updateSearch(msg)
+	}
+
+	switch {
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, keys.Help):
+		m.showHelp = true
+	case key.Matches(msg, keys.Search):
+		m.searching = true
+		m.nodeOffset = 0
+		return m, m.search.Focus()
+	case key.Matches(msg, keys.Cancel):
+		// Clear an active filter first, close on the next press
+		if m.search.Value() != "" {
+			m.search.Reset()
+		} else {
+			m = m.closeModal()
+		}
+	case key.Matches(msg, keys.Toggle):
+		if m.selectedNode < len(m.nodeRefs) {
+			if groupKey := m.nodeRefs[m.selectedNode].GroupKey; groupKey != "" {
+				m.collapsed[groupKey] = !m.collapsed[groupKey]
+			}
+		}
+	case key.Matches(msg, keys.Down):
+		m = m.moveSelection(1)
+	case key.Matches(msg, keys.Up):
+		m = m.moveSelection(-1)
+	case key.Matches(msg, keys.First):
+		m = m.moveSelection(-len(m.nodeRefs))
+	case key.Matches(msg, keys.Last):
+		m = m.moveSelection(len(m.nodeRefs))
+	case key.Matches(msg, keys.PageDown):
+		m = m.moveSelection(max(m.nodeListHeight()/2, 1))
+	case key.Matches(msg, keys.PageUp):
+		m = m.moveSelection(-max(m.nodeListHeight()/2, 1))
+	case key.Matches(msg, keys.AllCharts):
+		// Add all chart types for selected node to one pane, a new one if the modal was opened for a new pane
+		if m.selectedNode < len(m.nodeRefs) && m.nodeRefs[m.selectedNode].IsNode() {
+			skipped := 0
+			for _, chart := range keys.Charts {
+				var added bool
+				if m, added = m.addChart(chart.ChartType); added {
+					m.modalNewPane = false
+				} else {
+					skipped++
+				}
+			}
+			if skipped > 0 && m.status == "" {
+				m.status = fmt.Sprintf("Skipped %d of %d charts already in the pane", skipped, len(keys.Charts))
+			}
+		}
+		m = m.closeModal()
+	default:
+		for _, chart := range keys.Charts {
+			if key.Matches(msg, chart.Binding) {
+				m, _ = m.addChart(chart.ChartType)
+				m = m.closeModal()
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+// modalKeys returns the modal key bindings with only the charts the selected node offers
+func (m dashboardModel) modalKeys() ModalKeyMap {
+	keys := keyMap.Modal
+	if m.selectedNode < len(m.nodeRefs) {
+		ref := m.nodeRefs[m.selectedNode]
+		keys.Charts = slices.DeleteFunc(slices.Clone(keys.Charts), func(chart ChartBinding) bool {
+			return !ref.offersChart(chart.ChartType)
+		})
+	}
+	return keys
+}
+
+// updateSearch handles key presses while typing in the modal search box
+func (m dashboardModel) updateSearch(msg tea.KeyMsg) (dashboardModel, tea.Cmd) {
+	keys := keyMap.Modal
+	switch {
+	case key.Matches(msg, keys.Cancel):
+		m.searching = false
+		m.search.Blur()
+		m.search.Reset()
+		return m, nil
+	case key.Matches(msg, keys.Accept):
+		m.searching = false
+		m.search.Blur()
+		return m, nil
+	case key.Matches(msg, keys.NextMatch):
+		return m.moveSelection(1), nil
+	case key.Matches(msg, keys.PrevMatch):
+		return m.moveSelection(-1), nil
+	}
+
+	query := m.search.Value()
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	if m.search.Value() != query {
+		m = m.selectBestMatch()
+	}
+	return m, cmd
+}
+
+// closeModal hides the node selection modal and resets its state
+func (m dashboardModel) closeModal() dashboardModel {
+	m.showModal = false
+	m.modalNewPane = false
+	m.modalChartType = ""
+	m.searching = false
+	m.search.Blur()
+	m.search.Reset()
+	return m
+}
+
+// newSearchInput creates the search box for the node selection modal
+func newSearchInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "node or source name, label=value"
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(theme.Focus)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(theme.Muted)
+	return ti
+}
+
 // getGridColumns returns the number of columns in the current grid layout
 func (m dashboardModel) getGridColumns() int {
 	if len(m.activePanes) == 1 {
@@ -319,16 +539,17 @@ func (m dashboardModel) getGridColumns() int {
 // addChart adds a chart of the specified type for the currently selected node
 // Adds to the currently selected pane if one exists, otherwise creates a new pane
 // This allows mixing charts from different sources in the same pane
-func (m dashboardModel) addChart(chartType string) dashboardModel {
+// Reports whether the chart was added
+func (m dashboardModel) addChart(chartType string) (dashboardModel, bool) {
 	if m.selectedNode >= len(m.nodeRefs) {
-		return m
+		return m, false
 	}
 
 	selectedRef := m.nodeRefs[m.selectedNode]
 
-	// Don't add if it's a prometheus header
-	if selectedRef.Type == "prometheus" {
-		return m
+	// Don't add if it's a prometheus header or group
+	if !selectedRef.IsNode() {
+		return m, false
 	}
 
 	// Create the new chart
@@ -341,12 +562,15 @@ func (m dashboardModel) addChart(chartType string) dashboardModel {
 
 	// If modalNewPane is true, always create a new pane
 	if m.modalNewPane || len(m.activePanes) == 0 {
-		// Create new pane if we haven't reached the limit
-		if len(m.activePanes) < 9 {
-			newPane := NewTabSet().AddChart(newChart)
-			m.activePanes = append(m.activePanes, newPane)
-			m.selectedPane = len(m.activePanes) - 1 // Auto-select the new pane
+		// Don't create a new pane past the limit
+		if len(m.activePanes) >= 9 {
+			m.status = "Max 9 panes, chart not added"
+			return m, false
 		}
+		newPane := NewTabSet().AddChart(newChart)
+		m.activePanes = append(m.activePanes, newPane)
+		m.selectedPane = len(m.activePanes) - 1 // Auto-select the new pane
+		return m, true
 	} else if m.selectedPane < len(m.activePanes) {
 		// Add to currently selected pane
 		selectedPane := m.activePanes[m.selectedPane]
@@ -358,15 +582,16 @@ func (m dashboardModel) addChart(chartType string) dashboardModel {
 				chart.NodeRef.NodeName == selectedRef.NodeName &&
 				chart.ChartType == chartType {
 				// Exact duplicate, don't add
-				return m
+				return m, false
 			}
 		}
 
 		// Add chart to selected pane
 		selectedPane.AddChart(newChart)
+		return m, true
 	}
 
-	return m
+	return m, false
 }
 
 func (m dashboardModel) View() string {
@@ -380,13 +605,14 @@ func (m dashboardModel) View() string {
 	if len(m.activePanes) == 0 {
 		// Show instructions when no charts are active
 		helpStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
+			Foreground(theme.Muted).
 			Padding(2, 4)
 
+		dashboardKeys := keyMap.Dashboard
 		baseView = helpStyle.Render(
 			"No charts active\n\n" +
-				"Press 'n' to add a new pane\n" +
-				"Press 'hjkl' or arrow keys to navigate\n\n" +
+				"Press '" + dashboardKeys.NewPane.Help().Key + "' to add a new pane\n" +
+				"Press '" + dashboardKeys.Help.Help().Key + "' to list all key bindings\n\n" +
 				"Max 9 panes",
 		)
 	} else {
@@ -433,20 +659,32 @@ func (m dashboardModel) View() string {
 		// Wrap panes in a dynamic grid
 		panesView := Wrap(columns, renderedPanes...)
 
-		// Add status bar with help text
+		// Add status bar with help text, or the last status message
 		helpBar := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Background(lipgloss.Color("235")).
+			Background(theme.Surface).
 			Width(m.width).
 			Align(lipgloss.Center).
-			Render("n=New Pane  a=Add to Pane  []=Switch Tabs  x=Remove  hjkl/arrows=Navigate  q=Quit")
+			Render(newHelp(m.width, theme.Surface).ShortHelpView(keyMap.Dashboard.ShortHelp()))
+		if m.status != "" {
+			helpBar = lipgloss.NewStyle().
+				Background(theme.Surface).
+				Foreground(theme.Warning).
+				Width(m.width).
+				Align(lipgloss.Center).
+				Render(m.status)
+		}
 
 		baseView = panesView + "\n" + helpBar
 	}
 
 	// If modal is open, render it over the base view
 	if m.showModal {
-		return m.renderModal(baseView)
+		baseView = m.renderModal(baseView)
+	}
+
+	// The help overlay goes on top of everything
+	if m.showHelp {
+		return m.renderHelp()
 	}
 
 	return baseView
@@ -454,12 +692,13 @@ func (m dashboardModel) View() string {
 
 // renderModal renders the modal dialog for adding a new chart
 func (m dashboardModel) renderModal(baseView string) string {
-	// Calculate modal dimensions (centered, 60% of screen)
-	modalWidth := int(float64(m.width) * 0.6)
-	modalHeight := int(float64(m.height) * 0.6)
+	modalWidth, modalHeight := m.modalSize()
 
-	// Build node list content
-	nodeListContent := m.renderNodeList()
+	// Build node list content, with the search box above it while searching
+	nodeListContent := m.renderNodeList(modalWidth, m.nodeListHeight())
+	if m.showSearch() {
+		nodeListContent = m.search.View() + "\n" + nodeListContent
+	}
 
 	// Create modal content with appropriate title based on mode
 	var modalTitle string
@@ -473,9 +712,7 @@ func (m dashboardModel) renderModal(baseView string) string {
 		SetFocused(true)
 
 	// Create help text for modal
-	helpText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("c=CPU  m=Memory  s=Storage  n=Network  a=All Charts  ESC=Cancel")
+	helpText := newHelp(modalWidth, lipgloss.NoColor{}).ShortHelpView(m.modalKeys().ShortHelp())
 
 	modalContent := modalPane.Render() + "\n" + helpText
 
@@ -487,74 +724,52 @@ func (m dashboardModel) renderModal(baseView string) string {
 		lipgloss.Center,
 		modalContent,
 		lipgloss.WithWhitespaceChars("░"),
-		lipgloss.WithWhitespaceForeground(lipgloss.Color("235")),
+		lipgloss.WithWhitespaceForeground(theme.Subtle),
 	)
 }
 
-// renderNodeList builds the node list content string using lipgloss tree
-func (m dashboardModel) renderNodeList() string {
-	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("170")).
-		Bold(true)
-
-	sourceHeaderStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("214")).
-		Bold(true)
-
-	normalStyle := lipgloss.NewStyle()
-
-	// Build tree structure
-	var trees []string
-
-	i := 0
-	for i < len(m.nodeRefs) {
-		nodeRef := m.nodeRefs[i]
-
-		if nodeRef.Type == "prometheus" {
-			// Create tree for prometheus source with child nodes
-			var rootLabel string
-			if i == m.selectedNode {
-				rootLabel = selectedStyle.Render("▶ " + nodeRef.DisplayName)
-			} else {
-				rootLabel = sourceHeaderStyle.Render(nodeRef.DisplayName)
-			}
-
-			t := tree.New().Root(rootLabel)
+// renderHelp renders the overlay listing every key binding for the current mode
+func (m dashboardModel) renderHelp() string {
+	title := "Key Bindings - Dashboard"
+	var bindings help.KeyMap = keyMap.Dashboard
+	if m.showModal {
+		title = "Key Bindings - Select Node"
+		bindings = keyMap.Modal
+	}
 
-			// Add child nodes
-			i++
-			for i < len(m.nodeRefs) && m.nodeRefs[i].Type == "prometheus_node" && m.nodeRefs[i].SourceIndex == nodeRef.SourceIndex {
-				childRef := m.nodeRefs[i]
-				var childLabel string
-				if i == m.selectedNode {
-					childLabel = selectedStyle.Render("▶ " + childRef.DisplayName)
-				} else {
-					childLabel = normalStyle.Render(childRef.DisplayName)
-				}
-				t = t.Child(childLabel)
-				i++
-			}
+	content := newHelp(m.width, lipgloss.NoColor{}).FullHelpView(bindings.FullHelp())
+	helpPane := NewPane(title, lipgloss.Width(content), lipgloss.Height(content)+1).
+		SetContent(content).
+		SetFocused(true)
 
-			trees = append(trees, t.String())
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		helpPane.Render(),
+		lipgloss.WithWhitespaceChars("░"),
+		lipgloss.WithWhitespaceForeground(theme.Subtle),
+	)
+}
 
-		} else if nodeRef.Type == "node_exporter" {
-			// Create simple tree for node_exporter (no children)
-			var label string
-			if i == m.selectedNode {
-				label = selectedStyle.Render("▶ " + nodeRef.DisplayName)
-			} else {
-				label = sourceHeaderStyle.Render(nodeRef.DisplayName)
-			}
-			t := tree.New().Root(label)
-			trees = append(trees, t.String())
-			i++
-		} else {
-			// Shouldn't happen, but skip if orphaned prometheus_node
-			i++
-		}
+// newHelp creates a help view styled with the active theme
+func newHelp(width int, background lipgloss.TerminalColor) help.Model {
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Header).Background(background)
+	descStyle := lipgloss.NewStyle().Foreground(theme.Muted).Background(background)
+
+	h := help.New()
+	h.Width = width
+	h.Styles = help.Styles{
+		Ellipsis:       descStyle,
+		ShortKey:       keyStyle,
+		ShortDesc:      descStyle,
+		ShortSeparator: descStyle,
+		FullKey:        keyStyle,
+		FullDesc:       descStyle,
+		FullSeparator:  descStyle,
 	}
-
-	return strings.Join(trees, "\n")
+	return h
 }
 
 func Dashboard(sources []Cache, sourceNames []string) {
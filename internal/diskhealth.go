@@ -0,0 +1,333 @@
+package promtop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DiskHealth is the health of a node's software RAID arrays and drives
+type DiskHealth struct {
+	Arrays []MDArray    // Sorted by device
+	Drives []SmartDrive // Sorted by device, empty without smartctl_exporter
+	Errors []DiskErrors // Sorted by device then counter, empty unless the node publishes node_disk_* error counters
+}
+
+// MDArray is the state of a Linux software RAID array
+type MDArray struct {
+	Device   string
+	State    string // "active", "inactive", "recovering", "resync" or "check"
+	Active   float64
+	Failed   float64
+	Spare    float64
+	Required float64 // Disks the array is built from
+	Blocks   float64
+	Synced   float64 // Blocks synced by a running resync, recovery or check
+	SyncRate float64 // Blocks synced per second
+}
+
+// SmartDrive is the SMART state of a drive reported by smartctl_exporter
+type SmartDrive struct {
+	Device          string
+	Model           string
+	HasStatus       bool
+	Passed          bool    // Overall SMART self-assessment
+	Temperature     float64 // Degrees Celsius, 0 if not reported
+	PowerOnSeconds  float64
+	Reallocated     float64 // Reallocated sectors (ATA)
+	MediaErrors     float64 // Media and data integrity errors (NVMe)
+	CriticalWarning float64 // Critical warning bits (NVMe)
+}
+
+// DiskErrors is how fast a node_disk_* error counter of a device grows
+type DiskErrors struct {
+	Device string
+	Metric string  // Counter name without the node_disk_ prefix
+	Rate   float64 // Errors per second
+}
+
+// mdMetrics are the gauges behind the RAID part of the disk health chart
+var mdMetrics = []string{
+	"node_md_state",
+	"node_md_disks",
+	"node_md_disks_required",
+	"node_md_blocks",
+	"node_md_blocks_synced",
+}
+
+// mdSyncedMetric is the gauge the resync rate is taken from
+const mdSyncedMetric = "node_md_blocks_synced"
+
+// smartMetrics are the smartctl_exporter metrics behind the drive part of the disk health chart
+var smartMetrics = []string{
+	"smartctl_device",
+	"smartctl_device_smart_status",
+	"smartctl_device_temperature",
+	"smartctl_device_power_on_seconds",
+	"smartctl_device_attribute",
+	"smartctl_device_media_errors",
+	"smartctl_device_critical_warning",
+}
+
+// diskErrorPattern matches the node_disk_* error counters, which some platforms and textfile collectors publish
+const diskErrorPattern = "node_disk_.*error.*"
+
+// isDiskErrorMetric reports whether a metric name matches diskErrorPattern
+func isDiskErrorMetric(name string) bool {
+	return strings.HasPrefix(name, "node_disk_") && strings.Contains(name, "error")
+}
+
+// Drive temperatures that need attention, in degrees Celsius
+const (
+	driveWarningCelsius  = 60.0
+	driveCriticalCelsius = 70.0
+)
+
+// buildDiskHealth turns the RAID, SMART and disk error series into the disk health chart
+// syncRates are how fast node_md_blocks_synced grows per second
+// errorRates are the per-second rates of the disk error counters, keyed by metric name
+func buildDiskHealth(samples map[string][]sample, syncRates []sample, errorRates map[string][]sample) DiskHealth {
+	var health DiskHealth
+
+	arrays := make(map[string]*MDArray)
+	array := func(s sample) *MDArray {
+		name := s.labels["device"]
+		if arrays[name] == nil {
+			arrays[name] = &MDArray{Device: name}
+		}
+		return arrays[name]
+	}
+	for _, s := range samples["node_md_state"] {
+		// One series per state, the current one is 1
+		if s.value == 1 {
+			array(s).State = s.labels["state"]
+		}
+	}
+	for _, s := range samples["node_md_disks"] {
+		switch s.labels["state"] {
+		case "active":
+			array(s).Active = s.value
+		case "failed":
+			array(s).Failed = s.value
+		case "spare":
+			array(s).Spare = s.value
+		}
+	}
+	for _, s := range samples["node_md_disks_required"] {
+		array(s).Required = s.value
+	}
+	for _, s := range samples["node_md_blocks"] {
+		array(s).Blocks = s.value
+	}
+	for _, s := range samples["node_md_blocks_synced"] {
+		array(s).Synced = s.value
+	}
+	for _, s := range syncRates {
+		array(s).SyncRate = max(s.value, 0)
+	}
+	for _, a := range arrays {
+		health.Arrays = append(health.Arrays, *a)
+	}
+	sort.Slice(health.Arrays, func(i, j int) bool {
+		return health.Arrays[i].Device < health.Arrays[j].Device
+	})
+
+	drives := make(map[string]*SmartDrive)
+	drive := func(s sample) *SmartDrive {
+		name := s.labels["device"]
+		if drives[name] == nil {
+			drives[name] = &SmartDrive{Device: name}
+		}
+		return drives[name]
+	}
+	for _, s := range samples["smartctl_device"] {
+		drive(s).Model = s.labels["model_name"]
+	}
+	for _, s := range samples["smartctl_device_smart_status"] {
+		d := drive(s)
+		d.HasStatus = true
+		d.Passed = s.value == 1
+	}
+	for _, s := range samples["smartctl_device_temperature"] {
+		if s.labels["temperature_type"] == "current" {
+			drive(s).Temperature = s.value
+		}
+	}
+	for _, s := range samples["smartctl_device_power_on_seconds"] {
+		drive(s).PowerOnSeconds = s.value
+	}
+	for _, s := range samples["smartctl_device_attribute"] {
+		if s.labels["attribute_name"] == "Reallocated_Sector_Ct" && s.labels["attribute_value_type"] == "raw" {
+			drive(s).Reallocated = s.value
+		}
+	}
+	for _, s := range samples["smartctl_device_media_errors"] {
+		drive(s).MediaErrors = s.value
+	}
+	for _, s := range samples["smartctl_device_critical_warning"] {
+		drive(s).CriticalWarning = s.value
+	}
+	for _, d := range drives {
+		health.Drives = append(health.Drives, *d)
+	}
+	sort.Slice(health.Drives, func(i, j int) bool {
+		return health.Drives[i].Device < health.Drives[j].Device
+	})
+
+	for name, rates := range errorRates {
+		for _, s := range rates {
+			health.Errors = append(health.Errors, DiskErrors{
+				Device: s.labels["device"],
+				Metric: strings.TrimPrefix(name, "node_disk_"),
+				Rate:   s.value,
+			})
+		}
+	}
+	sort.Slice(health.Errors, func(i, j int) bool {
+		a, b := health.Errors[i], health.Errors[j]
+		if a.Device != b.Device {
+			return a.Device < b.Device
+		}
+		return a.Metric < b.Metric
+	})
+
+	return health
+}
+
+// status is critical for an array that is inactive, missing disks or has failed ones, and a warning while it syncs
+func (a MDArray) status() status {
+	switch {
+	case a.State == "inactive" || a.Failed > 0 || (a.Required > 0 && a.Active < a.Required):
+		return statusCritical
+	case a.State == "recovering" || a.State == "resync":
+		return statusWarning
+	}
+	return statusOK
+}
+
+// syncing reports whether a resync, recovery or check is running
+func (a MDArray) syncing() bool {
+	return a.Blocks > 0 && a.Synced > 0 && a.Synced < a.Blocks
+}
+
+// status is critical for a drive that fails its self-assessment or raises a critical warning
+// and a warning once sectors are reallocated, media errors are logged or it runs hot
+func (d SmartDrive) status() status {
+	st := statusFor(d.Temperature, driveWarningCelsius, driveCriticalCelsius)
+	if d.Reallocated > 0 || d.MediaErrors > 0 {
+		st = max(st, statusWarning)
+	}
+	if (d.HasStatus && !d.Passed) || d.CriticalWarning > 0 {
+		st = statusCritical
+	}
+	return st
+}
+
+// renderDiskHealth renders the disk health chart: RAID arrays, SMART drives, then disk error counters
+func renderDiskHealth(chart Chart, height int) string {
+	if chart.DiskHealthData == nil {
+		return "Waiting for data..."
+	}
+	health := *chart.DiskHealthData
+	if len(health.Arrays) == 0 && len(health.Drives) == 0 && len(health.Errors) == 0 {
+		return "No RAID, SMART or disk error metrics available on this node"
+	}
+
+	// Any of these counted at all is a problem
+	count := func(value float64, st status) string {
+		if value > 0 {
+			return st.render(fmt.Sprintf("%.0f", value))
+		}
+		return fmt.Sprintf("%.0f", value)
+	}
+
+	var sections []string
+
+	if len(health.Arrays) > 0 {
+		rows := [][]string{}
+		for _, a := range health.Arrays {
+			st := a.status()
+			sync := ""
+			if a.syncing() {
+				sync = fmt.Sprintf("%.1f%%", a.Synced/a.Blocks*100)
+				if a.SyncRate > 0 {
+					eta := time.Duration((a.Blocks - a.Synced) / a.SyncRate * float64(time.Second))
+					sync += ", " + formatUptime(eta) + " left"
+				}
+			}
+			rows = append(rows, []string{
+				a.Device,
+				st.render(a.State),
+				st.render(fmt.Sprintf("%.0f/%.0f", a.Active, a.Required)),
+				count(a.Failed, statusCritical),
+				fmt.Sprintf("%.0f", a.Spare),
+				sync,
+			})
+		}
+		sections = append(sections, NewWrapTable().
+			MaxHeight(height).
+			Headers("Array", "State", "Disks", "Failed", "Spare", "Sync").
+			Rows(rows...).
+			Render())
+	}
+
+	if len(health.Drives) > 0 {
+		rows := [][]string{}
+		for _, d := range health.Drives {
+			st := d.status()
+			smart := ""
+			if d.HasStatus {
+				smart = "FAILED"
+				if d.Passed {
+					smart = "passed"
+				}
+			}
+			temperature := ""
+			if d.Temperature > 0 {
+				temperature = fmt.Sprintf("%.0f°C", d.Temperature)
+			}
+			powerOn := ""
+			if d.PowerOnSeconds > 0 {
+				powerOn = fmt.Sprintf("%.0f h", d.PowerOnSeconds/3600)
+			}
+			rows = append(rows, []string{
+				st.render(d.Device),
+				d.Model,
+				st.render(smart),
+				statusFor(d.Temperature, driveWarningCelsius, driveCriticalCelsius).render(temperature),
+				powerOn,
+				count(d.Reallocated, statusWarning),
+				count(d.MediaErrors, statusWarning),
+			})
+		}
+		sections = append(sections, NewWrapTable().
+			MaxHeight(height).
+			Headers("Drive", "Model", "SMART", "Temp", "Power on", "Realloc", "Media err").
+			Rows(rows...).
+			Render())
+	}
+
+	if len(health.Errors) > 0 {
+		rows := [][]string{}
+		for _, e := range health.Errors {
+			// Any new error is a warning
+			st := statusOK
+			if e.Rate > 0 {
+				st = statusWarning
+			}
+			rows = append(rows, []string{
+				st.render(e.Device),
+				st.render(e.Metric),
+				st.render(fmt.Sprintf("%.2f/s", e.Rate)),
+			})
+		}
+		sections = append(sections, NewWrapTable().
+			MaxHeight(height).
+			Headers("Device", "Error counter", "Rate").
+			Rows(rows...).
+			Render())
+	}
+
+	return strings.Join(sections, "\n")
+}
@@ -0,0 +1,68 @@
+package promtop
+
+import (
+	"strings"
+	"testing"
+)
+
+// labelled returns a sample with the given label names and values
+func labelled(value float64, labels ...string) sample {
+	s := sample{labels: make(map[string]string), value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		s.labels[labels[i]] = labels[i+1]
+	}
+	return s
+}
+
+func TestBuildDiskHealth(t *testing.T) {
+	// A mirror rebuilding onto a new disk, a drive that has started to reallocate sectors and another logging IO errors
+	health := buildDiskHealth(map[string][]sample{
+		"node_md_state": {
+			labelled(0, "device", "md0", "state", "active"),
+			labelled(1, "device", "md0", "state", "recovering"),
+		},
+		"node_md_disks": {
+			labelled(1, "device", "md0", "state", "active"),
+			labelled(1, "device", "md0", "state", "spare"),
+		},
+		"node_md_disks_required":       {labelled(2, "device", "md0")},
+		"node_md_blocks":               {labelled(1000, "device", "md0")},
+		"node_md_blocks_synced":        {labelled(250, "device", "md0")},
+		"smartctl_device":              {labelled(1, "device", "sda", "model_name", "ST4000VN008-2DR166")},
+		"smartctl_device_smart_status": {labelled(1, "device", "sda")},
+		"smartctl_device_attribute": {
+			labelled(8, "device", "sda", "attribute_name", "Reallocated_Sector_Ct", "attribute_value_type", "raw"),
+			labelled(100, "device", "sda", "attribute_name", "Reallocated_Sector_Ct", "attribute_value_type", "value"),
+		},
+	}, []sample{labelled(25, "device", "md0")}, map[string][]sample{
+		"node_disk_io_errors_total": {
+			labelled(0.5, "device", "sdb"),
+			labelled(0, "device", "sda"),
+		},
+	})
+
+	if len(health.Arrays) != 1 || len(health.Drives) != 1 || len(health.Errors) != 2 {
+		t.Fatalf("buildDiskHealth() = %+v, want one array, one drive and two error counters", health)
+	}
+	array := health.Arrays[0]
+	if array.State != "recovering" || array.Active != 1 || array.Required != 2 || array.SyncRate != 25 {
+		t.Errorf("array = %+v, want md0 recovering with 1 of 2 disks at 25 blocks/s", array)
+	}
+	if array.status() != statusCritical {
+		t.Errorf("degraded array status = %v, want critical", array.status())
+	}
+	if drive := health.Drives[0]; drive.Reallocated != 8 || drive.status() != statusWarning {
+		t.Errorf("drive = %+v with status %v, want 8 reallocated sectors and a warning", drive, drive.status())
+	}
+	if want := (DiskErrors{Device: "sdb", Metric: "io_errors_total", Rate: 0.5}); health.Errors[1] != want {
+		t.Errorf("errors = %+v, want %+v after sda", health.Errors, want)
+	}
+
+	// 750 blocks left at 25 a second
+	rendered := renderDiskHealth(Chart{DiskHealthData: &health}, 30)
+	for _, want := range []string{"25.0%", "left", "ST4000VN008-2DR166", "io_errors_total", "0.50/s"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("renderDiskHealth() is missing %q:\n%s", want, rendered)
+		}
+	}
+}
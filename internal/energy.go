@@ -0,0 +1,105 @@
+package promtop
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// raplMetrics are the RAPL energy counters behind the energy chart, keyed by domain
+// Package energy includes the cores, DRAM is measured separately
+var raplMetrics = map[string]string{
+	"package": "node_rapl_package_joules_total",
+	"core":    "node_rapl_core_joules_total",
+	"dram":    "node_rapl_dram_joules_total",
+}
+
+// raplDomains are the domains in the order they are shown, with their labels
+var raplDomains = []struct{ name, label string }{
+	{"package", ""},
+	{"core", " cores"},
+	{"dram", " DRAM"},
+}
+
+// buildEnergy turns the per-zone rates of the RAPL counters into watts keyed by domain and package index,
+// e.g. package:0, with the node's total under "total"
+func buildEnergy(rates map[string][]sample) map[string]float64 {
+	energy := make(map[string]float64)
+	for domain, series := range rates {
+		for _, s := range series {
+			// Joules per second are watts
+			energy[domain+":"+s.labels["index"]] += s.value
+			if domain == "package" || domain == "dram" {
+				energy["total"] += s.value
+			}
+		}
+	}
+	return energy
+}
+
+// renderEnergy renders the energy chart: watts per package and domain with their trend, then the totals
+func renderEnergy(chart Chart, width, height int) string {
+	if chart.EnergyData == nil {
+		return "Waiting for data..."
+	}
+	if len(chart.EnergyData) == 0 {
+		return "RAPL energy metrics are not available on this node"
+	}
+
+	latest := func(name string) (float64, bool) {
+		history := chart.EnergyData[name]
+		if len(history) == 0 {
+			return 0, false
+		}
+		return history[len(history)-1], true
+	}
+
+	// Package indexes seen in any domain
+	var indexes []string
+	for name := range chart.EnergyData {
+		if _, index, ok := strings.Cut(name, ":"); ok && !slices.Contains(indexes, index) {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return numericLess(indexes[i], indexes[j])
+	})
+
+	// Leave room for the other columns and the table borders
+	trendWidth := max(width-40, 0)
+
+	// Scale each trend to its own peak, so changes stand out whatever the power draw
+	trend := func(name string) string {
+		return sparkline(chart.EnergyData[name], trendWidth, max(slices.Max(chart.EnergyData[name]), 1))
+	}
+
+	rows := [][]string{}
+	for _, index := range indexes {
+		for _, domain := range raplDomains {
+			name := domain.name + ":" + index
+			watts, ok := latest(name)
+			if !ok {
+				continue
+			}
+			rows = append(rows, []string{
+				"Package " + index + domain.label,
+				fmt.Sprintf("%.1f W", watts),
+				trend(name),
+			})
+		}
+	}
+	if total, ok := latest("total"); ok {
+		rows = append(rows, []string{"Node total", fmt.Sprintf("%.1f W", total), trend("total")})
+	}
+	if total, ok := latest("source_total"); ok {
+		rows = append(rows, []string{"Source total", fmt.Sprintf("%.1f W", total), trend("source_total")})
+	}
+
+	t := NewWrapTable().
+		MaxHeight(height).
+		Headers("Domain", "Power", "Trend").
+		Rows(rows...)
+
+	return t.Render() + "\nTotals are package plus DRAM, package includes the cores"
+}
@@ -0,0 +1,52 @@
+package promtop
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recordedRAPLRates are the per-second rates of a two socket node's RAPL counters, joules per second are watts
+const recordedRAPLRates = `node_rapl_package_joules_total{index="0",path="/sys/class/powercap/intel-rapl:0"} 35.5
+node_rapl_package_joules_total{index="1",path="/sys/class/powercap/intel-rapl:1"} 30.25
+node_rapl_core_joules_total{index="0",path="/sys/class/powercap/intel-rapl:0:0"} 20
+node_rapl_dram_joules_total{index="0",path="/sys/class/powercap/intel-rapl:0:1"} 4.5
+node_rapl_dram_joules_total{index="1",path="/sys/class/powercap/intel-rapl:1:1"} 3.75
+`
+
+func TestBuildEnergy(t *testing.T) {
+	tests := []struct {
+		name     string
+		recorded string
+		want     map[string]float64
+	}{
+		{
+			name:     "two packages",
+			recorded: recordedRAPLRates,
+			want: map[string]float64{
+				"package:0": 35.5,
+				"package:1": 30.25,
+				"core:0":    20,
+				"dram:0":    4.5,
+				"dram:1":    3.75,
+				"total":     74, // Package and DRAM, the cores are part of the package
+			},
+		},
+		{
+			name:     "waiting for rates",
+			recorded: "",
+			want:     map[string]float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorded := parseSamples(t, tt.recorded)
+			rates := make(map[string][]sample)
+			for domain, metric := range raplMetrics {
+				rates[domain] = recorded[metric]
+			}
+			if got := buildEnergy(rates); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildEnergy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package promtop
+
+import "strings"
+
+// labelMatcher matches a node label against a value, e.g. env=prod or env!=dev
+type labelMatcher struct {
+	name   string
+	value  string
+	negate bool
+}
+
+// nodeFilter narrows the node list in the selection modal
+// Words containing = or != are label filters, everything else is fuzzy matched against names
+type nodeFilter struct {
+	pattern string
+	labels  []labelMatcher
+}
+
+// nodeMatch is the result of matching a node against a filter
+type nodeMatch struct {
+	score     int
+	positions []int // Matched rune positions in the display name
+}
+
+// parseNodeFilter splits a search query into a fuzzy pattern and label filters
+func parseNodeFilter(query string) nodeFilter {
+	var filter nodeFilter
+	var words []string
+	for _, word := range strings.Fields(query) {
+		if name, value, ok := strings.Cut(word, "!="); ok && name != "" {
+			filter.labels = append(filter.labels, labelMatcher{name: name, value: value, negate: true})
+		} else if name, value, ok := strings.Cut(word, "="); ok && name != "" {
+			filter.labels = append(filter.labels, labelMatcher{name: name, value: value})
+		} else {
+			words = append(words, word)
+		}
+	}
+	filter.pattern = strings.Join(words, "")
+	return filter
+}
+
+// empty reports whether the filter matches everything
+func (f nodeFilter) empty() bool {
+	return f.pattern == "" && len(f.labels) == 0
+}
+
+// match checks a node against the filter
+// The pattern can match the node's display name, its address or its source name
+func (f nodeFilter) match(ref NodeRef) (nodeMatch, bool) {
+	if !f.matchLabels(ref) {
+		return nodeMatch{}, false
+	}
+
+	if score, positions, ok := fuzzyMatch(f.pattern, ref.DisplayName); ok {
+		return nodeMatch{score: score, positions: positions}, true
+	}
+	if score, _, ok := fuzzyMatch(f.pattern, ref.NodeName); ok {
+		return nodeMatch{score: score}, true
+	}
+	if score, _, ok := fuzzyMatch(f.pattern, ref.SourceName); ok {
+		return nodeMatch{score: score}, true
+	}
+	return nodeMatch{}, false
+}
+
+// matchLabels checks a node against the label filters only
+func (f nodeFilter) matchLabels(ref NodeRef) bool {
+	for _, l := range f.labels {
+		value, ok := ref.Labels[l.name]
+		if (ok && value == l.value) == l.negate {
+			return false
+		}
+	}
+	return true
+}
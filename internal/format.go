@@ -0,0 +1,17 @@
+package promtop
+
+import "fmt"
+
+// formatBytes formats a byte count with the largest binary unit that keeps it above 1
+func formatBytes(bytes float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", bytes, units[unit])
+	}
+	return fmt.Sprintf("%.2f %s", bytes, units[unit])
+}
@@ -0,0 +1,197 @@
+package promtop
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CPUFrequency is the frequency and throttling state of a node's CPUs
+type CPUFrequency struct {
+	CPUs             []CoreFrequency    // One per logical CPU, sorted by package then CPU number
+	CoreThrottles    map[string]float64 // Core throttle events per second, summed per package
+	PackageThrottles map[string]float64 // Package throttle events per second, per package
+}
+
+// CoreFrequency is the frequency of a logical CPU in hertz, limits are 0 if not published
+type CoreFrequency struct {
+	CPU         string
+	Package     string
+	Core        string
+	Current     float64 // Current scaling frequency
+	Min         float64 // Lowest frequency the governor may pick
+	Max         float64 // Highest frequency the governor may pick
+	HardwareMax float64 // Highest frequency the CPU supports
+}
+
+// frequencyMetrics are the gauges behind the frequency chart
+var frequencyMetrics = []string{
+	"node_cpu_scaling_frequency_hertz",
+	"node_cpu_scaling_frequency_min_hertz",
+	"node_cpu_scaling_frequency_max_hertz",
+	"node_cpu_frequency_max_hertz",
+	"node_cpu_info",
+}
+
+// Throttle counters behind the frequency chart
+const (
+	coreThrottlesMetric    = "node_cpu_core_throttles_total"
+	packageThrottlesMetric = "node_cpu_package_throttles_total"
+)
+
+// buildCPUFrequency joins the frequency gauges with node_cpu_info to place each CPU in its package
+// and sums the throttle rates per package
+func buildCPUFrequency(samples map[string][]sample, coreThrottles, packageThrottles []sample) CPUFrequency {
+	byCPU := make(map[string]*CoreFrequency)
+	cpu := func(s sample) *CoreFrequency {
+		name := s.labels["cpu"]
+		if byCPU[name] == nil {
+			byCPU[name] = &CoreFrequency{CPU: name}
+		}
+		return byCPU[name]
+	}
+
+	for _, s := range samples["node_cpu_info"] {
+		c := cpu(s)
+		c.Package = s.labels["package"]
+		c.Core = s.labels["core"]
+	}
+	for _, s := range samples["node_cpu_scaling_frequency_hertz"] {
+		cpu(s).Current = s.value
+	}
+	for _, s := range samples["node_cpu_scaling_frequency_min_hertz"] {
+		cpu(s).Min = s.value
+	}
+	for _, s := range samples["node_cpu_scaling_frequency_max_hertz"] {
+		cpu(s).Max = s.value
+	}
+	for _, s := range samples["node_cpu_frequency_max_hertz"] {
+		cpu(s).HardwareMax = s.value
+	}
+
+	freq := CPUFrequency{
+		CoreThrottles:    make(map[string]float64),
+		PackageThrottles: make(map[string]float64),
+	}
+	for _, c := range byCPU {
+		// node_cpu_info alone doesn't make a reading
+		if c.Current > 0 {
+			freq.CPUs = append(freq.CPUs, *c)
+		}
+	}
+	sort.Slice(freq.CPUs, func(i, j int) bool {
+		a, b := freq.CPUs[i], freq.CPUs[j]
+		if a.Package != b.Package {
+			return numericLess(a.Package, b.Package)
+		}
+		return numericLess(a.CPU, b.CPU)
+	})
+
+	for _, s := range coreThrottles {
+		freq.CoreThrottles[s.labels["package"]] += s.value
+	}
+	for _, s := range packageThrottles {
+		freq.PackageThrottles[s.labels["package"]] += s.value
+	}
+	return freq
+}
+
+// numericLess orders strings as numbers when they are, so CPU 10 comes after CPU 9
+func numericLess(a, b string) bool {
+	numA, errA := strconv.Atoi(a)
+	numB, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return numA < numB
+	}
+	return a < b
+}
+
+// formatHertz formats a frequency in MHz or GHz
+func formatHertz(hz float64) string {
+	if hz == 0 {
+		return ""
+	}
+	if hz >= 1e9 {
+		return fmt.Sprintf("%.2f GHz", hz/1e9)
+	}
+	return fmt.Sprintf("%.0f MHz", hz/1e6)
+}
+
+// renderFrequency renders the frequency chart: a line of throttling per package, then each CPU against its limits
+func renderFrequency(chart Chart, width, height int) string {
+	if chart.FrequencyData == nil {
+		return "Waiting for data..."
+	}
+	freq := *chart.FrequencyData
+	if len(freq.CPUs) == 0 && len(freq.CoreThrottles) == 0 && len(freq.PackageThrottles) == 0 {
+		return "CPU frequency metrics are not available on this node"
+	}
+
+	var b strings.Builder
+
+	// Throttling per package, any throttling at all is worth knowing about
+	packages := make(map[string]bool)
+	for _, c := range freq.CPUs {
+		packages[c.Package] = true
+	}
+	for pkg := range freq.CoreThrottles {
+		packages[pkg] = true
+	}
+	for pkg := range freq.PackageThrottles {
+		packages[pkg] = true
+	}
+	names := make([]string, 0, len(packages))
+	for pkg := range packages {
+		names = append(names, pkg)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return numericLess(names[i], names[j])
+	})
+	for _, pkg := range names {
+		core, pack := freq.CoreThrottles[pkg], freq.PackageThrottles[pkg]
+		st := statusOK
+		if core > 0 || pack > 0 {
+			st = statusWarning
+		}
+		label := "Package " + pkg
+		if pkg == "" {
+			label = "Package ?"
+		}
+		b.WriteString(st.render(fmt.Sprintf("%s: %.2f core throttles/s, %.2f package throttles/s", label, core, pack)))
+		b.WriteString("\n")
+	}
+
+	if len(freq.CPUs) == 0 {
+		return b.String()
+	}
+
+	// Leave room for the other columns and the table borders
+	barWidth := min(max(width-65, 5), 30)
+
+	rows := [][]string{}
+	for _, c := range freq.CPUs {
+		ceiling := max(c.HardwareMax, c.Max)
+		bar := ""
+		if ceiling > 0 {
+			filled := min(int(c.Current/ceiling*float64(barWidth)), barWidth)
+			bar = strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+		}
+		rows = append(rows, []string{
+			c.CPU,
+			c.Package,
+			formatHertz(c.Current),
+			formatHertz(c.Min),
+			formatHertz(c.Max),
+			formatHertz(c.HardwareMax),
+			bar,
+		})
+	}
+
+	t := NewWrapTable().
+		MaxHeight(height-len(names)).
+		Headers("CPU", "Pkg", "Current", "Min", "Max", "HW max", "Of max").
+		Rows(rows...)
+	b.WriteString(t.Render())
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package promtop
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyMatch checks whether the characters of pattern appear in order in text, ignoring case
+// Returns a score (higher is better) and the rune positions in text that matched
+// Consecutive matches and matches at the start of a word score higher
+func fuzzyMatch(pattern, text string) (int, []int, bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+
+	patternRunes := []rune(strings.ToLower(pattern))
+	textRunes := []rune(text)
+
+	// try every starting point for the first character and keep the best alignment
+	bestScore := -1
+	var bestPositions []int
+	for start, r := range textRunes {
+		if unicode.ToLower(r) != patternRunes[0] {
+			continue
+		}
+		score, positions, ok := matchFrom(patternRunes, textRunes, start)
+		if ok && score > bestScore {
+			bestScore = score
+			bestPositions = positions
+		}
+	}
+
+	if bestScore < 0 {
+		return 0, nil, false
+	}
+	// prefer shorter texts when everything else is equal
+	return bestScore*100 - len(textRunes), bestPositions, true
+}
+
+// matchFrom greedily matches the pattern against the text starting at start
+func matchFrom(pattern, text []rune, start int) (int, []int, bool) {
+	positions := make([]int, 0, len(pattern))
+	score := 0
+	p := 0
+	for i := start; i < len(text) && p < len(pattern); i++ {
+		if unicode.ToLower(text[i]) != pattern[p] {
+			continue
+		}
+
+		score++
+		if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+			score += 3
+		}
+		if i == 0 || !unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1]) {
+			score += 2
+		}
+		positions = append(positions, i)
+		p++
+	}
+	return score, positions, p == len(pattern)
+}
+
+// highlightMatches renders the runes at positions with the match style and the rest with base
+func highlightMatches(text string, positions []int, base, match func(...string) string) string {
+	if len(positions) == 0 {
+		return base(text)
+	}
+
+	matched := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		matched[pos] = true
+	}
+
+	var b strings.Builder
+	var run []rune
+	runMatched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(match(string(run)))
+		} else {
+			b.WriteString(base(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		if matched[i] != runMatched {
+			flush()
+			runMatched = matched[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
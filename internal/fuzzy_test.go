@@ -0,0 +1,55 @@
+package promtop
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{name: "empty pattern", pattern: "", text: "web-01", ok: true},
+		{name: "prefix", pattern: "web", text: "web-01", ok: true, positions: []int{0, 1, 2}},
+		{name: "gaps", pattern: "w01", text: "web-01", ok: true, positions: []int{0, 4, 5}},
+		{name: "ignores case", pattern: "WEB", text: "Web-01", ok: true, positions: []int{0, 1, 2}},
+		{name: "prefers a word start", pattern: "db", text: "web-db-01", ok: true, positions: []int{4, 5}},
+		{name: "out of order", pattern: "bew", text: "web-01", ok: false},
+		{name: "missing character", pattern: "webx", text: "web-01", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := fuzzyMatch(tt.pattern, tt.text)
+			if ok != tt.ok || !slices.Equal(positions, tt.positions) {
+				t.Errorf("fuzzyMatch(%q, %q) = %v, %v, want %v, %v", tt.pattern, tt.text, positions, ok, tt.positions, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatchRanking(t *testing.T) {
+	// Each pair is a better match than a worse one for the pattern
+	tests := []struct {
+		name    string
+		pattern string
+		better  string
+		worse   string
+	}{
+		{name: "consecutive", pattern: "db", better: "db-01", worse: "d-b-01"},
+		{name: "word start", pattern: "db", better: "web-db", worse: "webdb"},
+		{name: "shorter", pattern: "web", better: "web-1", worse: "web-10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			better, _, _ := fuzzyMatch(tt.pattern, tt.better)
+			worse, _, _ := fuzzyMatch(tt.pattern, tt.worse)
+			if better <= worse {
+				t.Errorf("fuzzyMatch(%q) scores %q %d, not above %q %d", tt.pattern, tt.better, better, tt.worse, worse)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package promtop
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// nodeGroup is a set of nodes that share the same values for the grouping labels
+type nodeGroup struct {
+	values []string // One value per grouping label, in order
+	nodes  []string // Node names, in the order they were given
+}
+
+// missingLabelValue is used for nodes without one of the grouping labels
+const missingLabelValue = "(none)"
+
+// groupNodes splits nodes into groups by the values of the groupBy labels
+// Groups are sorted by their values so nested groups come out in tree order
+// With no grouping labels everything ends up in a single group
+func groupNodes(nodes []string, labels map[string]map[string]string, groupBy []string) []nodeGroup {
+	groups := make(map[string]*nodeGroup)
+	for _, node := range nodes {
+		values := make([]string, len(groupBy))
+		for i, name := range groupBy {
+			values[i] = labels[node][name]
+			if values[i] == "" {
+				values[i] = missingLabelValue
+			}
+		}
+
+		key := strings.Join(values, "\x00")
+		if groups[key] == nil {
+			groups[key] = &nodeGroup{values: values}
+		}
+		groups[key].nodes = append(groups[key].nodes, node)
+	}
+
+	sorted := make([]nodeGroup, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return slices.Compare(sorted[i].values, sorted[j].values) < 0
+	})
+	return sorted
+}
+
+// groupKey identifies a group within a source for remembering which groups are collapsed
+func groupKey(sourceName string, groupBy, values []string) string {
+	parts := []string{sourceName}
+	for i, value := range values {
+		parts = append(parts, groupBy[i]+"="+value)
+	}
+	return strings.Join(parts, "/")
+}
+
+// groupCounts returns the number of nodes under each group and its parent groups, keyed by groupKey
+func groupCounts(sourceName string, groupBy []string, groups []nodeGroup) map[string]int {
+	counts := make(map[string]int)
+	for _, group := range groups {
+		for level := range group.values {
+			counts[groupKey(sourceName, groupBy, group.values[:level+1])] += len(group.nodes)
+		}
+	}
+	return counts
+}
+
+// groupedRows lays out the table rows of a fleet-wide chart under the groups of config.GroupBy, nested like the node list
+// Nodes keep their order within a group, and sources without labels aren't grouped
+func groupedRows(nodes []string, labels map[string]map[string]string, columns int, row func(node string) []string) [][]string {
+	groupBy := config.GroupBy
+	if len(groupBy) == 0 || len(labels) == 0 {
+		rows := make([][]string, 0, len(nodes))
+		for _, node := range nodes {
+			rows = append(rows, row(node))
+		}
+		return rows
+	}
+
+	groupStyle := lipgloss.NewStyle().Foreground(theme.Title)
+	var rows [][]string
+	var previous []string
+	for _, group := range groupNodes(nodes, labels, groupBy) {
+		// Add a group row for each level that differs from the previous group
+		for level, value := range group.values {
+			if previous != nil && slices.Equal(previous[:level+1], group.values[:level+1]) {
+				continue
+			}
+			header := make([]string, columns)
+			header[0] = groupStyle.Render(strings.Repeat("  ", level) + groupBy[level] + "=" + value)
+			rows = append(rows, header)
+		}
+		previous = group.values
+		for _, node := range group.nodes {
+			cells := row(node)
+			cells[0] = strings.Repeat("  ", len(groupBy)) + cells[0]
+			rows = append(rows, cells)
+		}
+	}
+	return rows
+}
@@ -0,0 +1,98 @@
+package promtop
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Four targets as the up series labels them, one of them without a role
+var (
+	targetNodes  = []string{"10.0.0.1:9100", "10.0.0.2:9100", "10.0.0.3:9100", "10.0.0.4:9100"}
+	targetLabels = map[string]map[string]string{
+		"10.0.0.1:9100": {"env": "prod", "role": "web"},
+		"10.0.0.2:9100": {"env": "prod", "role": "db"},
+		"10.0.0.3:9100": {"env": "dev", "role": "web"},
+		"10.0.0.4:9100": {"env": "prod"},
+	}
+)
+
+func TestGroupNodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		groupBy []string
+		want    []nodeGroup
+	}{
+		{
+			name: "no grouping",
+			want: []nodeGroup{{values: []string{}, nodes: targetNodes}},
+		},
+		{
+			name:    "one label",
+			groupBy: []string{"env"},
+			want: []nodeGroup{
+				{values: []string{"dev"}, nodes: []string{"10.0.0.3:9100"}},
+				{values: []string{"prod"}, nodes: []string{"10.0.0.1:9100", "10.0.0.2:9100", "10.0.0.4:9100"}},
+			},
+		},
+		{
+			name:    "nested with a missing label",
+			groupBy: []string{"env", "role"},
+			want: []nodeGroup{
+				{values: []string{"dev", "web"}, nodes: []string{"10.0.0.3:9100"}},
+				{values: []string{"prod", "(none)"}, nodes: []string{"10.0.0.4:9100"}},
+				{values: []string{"prod", "db"}, nodes: []string{"10.0.0.2:9100"}},
+				{values: []string{"prod", "web"}, nodes: []string{"10.0.0.1:9100"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupNodes(targetNodes, targetLabels, tt.groupBy); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupNodes() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupCounts(t *testing.T) {
+	// A parent group counts the nodes of all the groups under it
+	groupBy := []string{"env", "role"}
+	counts := groupCounts("prometheus", groupBy, groupNodes(targetNodes, targetLabels, groupBy))
+	for key, want := range map[string]int{
+		"prometheus/env=prod":             3,
+		"prometheus/env=prod/role=(none)": 1,
+		"prometheus/env=dev/role=web":     1,
+	} {
+		if counts[key] != want {
+			t.Errorf("groupCounts()[%q] = %d, want %d", key, counts[key], want)
+		}
+	}
+}
+
+func TestGroupedRows(t *testing.T) {
+	previous := config.GroupBy
+	t.Cleanup(func() { config.GroupBy = previous })
+	config.GroupBy = []string{"env", "role"}
+
+	row := func(node string) []string { return []string{node, "1"} }
+	want := [][]string{
+		{"env=dev", ""},
+		{"  role=web", ""},
+		{"    10.0.0.3:9100", "1"},
+		{"env=prod", ""},
+		{"  role=(none)", ""},
+		{"    10.0.0.4:9100", "1"},
+		{"  role=db", ""},
+		{"    10.0.0.2:9100", "1"},
+		{"  role=web", ""},
+		{"    10.0.0.1:9100", "1"},
+	}
+	if got := groupedRows(targetNodes, targetLabels, 2, row); !reflect.DeepEqual(got, want) {
+		t.Errorf("groupedRows() = %q, want %q", got, want)
+	}
+
+	// node_exporter sources have no labels to group by
+	if got := groupedRows(targetNodes, nil, 2, row); len(got) != len(targetNodes) {
+		t.Errorf("groupedRows() without labels = %q, want only the nodes", got)
+	}
+}
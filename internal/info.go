@@ -0,0 +1,142 @@
+package promtop
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// infoMetrics are the metrics the info chart is built from
+var infoMetrics = []string{
+	"node_uname_info",
+	"node_os_info",
+	"node_cpu_info",
+	"node_dmi_info",
+	"node_boot_time_seconds",
+	"node_memory_MemTotal_bytes",
+	"node_memory_total_bytes",
+}
+
+// buildNodeInfo turns the series of the info metrics into the fields shown by the info chart
+// series holds the labels of each series keyed by metric name, values the first sample of each metric
+// Numbers are kept as strings so they can be formatted when rendered
+func buildNodeInfo(series map[string][]map[string]string, values map[string]float64) map[string]string {
+	info := make(map[string]string)
+	set := func(field, value string) {
+		if value != "" {
+			info[field] = value
+		}
+	}
+
+	if uname := series["node_uname_info"]; len(uname) > 0 {
+		set("hostname", uname[0]["nodename"])
+		set("kernel", uname[0]["release"])
+		set("arch", uname[0]["machine"])
+	}
+
+	if osInfo := series["node_os_info"]; len(osInfo) > 0 {
+		set("os", osInfo[0]["pretty_name"])
+		if info["os"] == "" {
+			set("os", osInfo[0]["name"]+" "+osInfo[0]["version"])
+		}
+	}
+
+	// node_cpu_info has a series per logical CPU
+	if cpus := series["node_cpu_info"]; len(cpus) > 0 {
+		cores := make(map[string]bool)
+		packages := make(map[string]bool)
+		for _, cpu := range cpus {
+			cores[cpu["package"]+"/"+cpu["core"]] = true
+			packages[cpu["package"]] = true
+		}
+		set("cpu_model", cpus[0]["model_name"])
+		set("cpu_sockets", strconv.Itoa(len(packages)))
+		set("cpu_cores", strconv.Itoa(len(cores)))
+		set("cpu_threads", strconv.Itoa(len(cpus)))
+	}
+
+	if dmi := series["node_dmi_info"]; len(dmi) > 0 {
+		set("vendor", dmi[0]["system_vendor"])
+		set("product", dmi[0]["product_name"])
+		set("bios", dmi[0]["bios_version"])
+		if date := dmi[0]["bios_date"]; date != "" && info["bios"] != "" {
+			info["bios"] += " (" + date + ")"
+		}
+	}
+
+	if boot, ok := values["node_boot_time_seconds"]; ok {
+		set("boot_time", strconv.FormatFloat(boot, 'f', 0, 64))
+	}
+
+	// Linux reports MemTotal, macOS total
+	if total, ok := values["node_memory_MemTotal_bytes"]; ok {
+		set("memory_total", strconv.FormatFloat(total, 'f', 0, 64))
+	} else if total, ok := values["node_memory_total_bytes"]; ok {
+		set("memory_total", strconv.FormatFloat(total, 'f', 0, 64))
+	}
+
+	return info
+}
+
+// formatUptime formats a duration as days, hours and minutes
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+// renderInfo renders the info chart: what the machine is, followed by its target labels
+func renderInfo(chart Chart, height int) string {
+	if len(chart.InfoData) == 0 {
+		return "Waiting for data..."
+	}
+	info := chart.InfoData
+
+	rows := [][]string{}
+	add := func(name, value string) {
+		if value != "" {
+			rows = append(rows, []string{name, value})
+		}
+	}
+
+	add("Hostname", info["hostname"])
+	add("OS", info["os"])
+	add("Kernel", info["kernel"])
+	add("Architecture", info["arch"])
+	add("CPU", info["cpu_model"])
+	add("Sockets", info["cpu_sockets"])
+	add("Cores", info["cpu_cores"])
+	add("Threads", info["cpu_threads"])
+	if total, err := strconv.ParseFloat(info["memory_total"], 64); err == nil {
+		add("Memory", fmt.Sprintf("%.2f GB", total/(1024*1024*1024)))
+	}
+	if boot, err := strconv.ParseInt(info["boot_time"], 10, 64); err == nil {
+		booted := time.Unix(boot, 0)
+		add("Uptime", formatUptime(time.Since(booted)))
+		add("Booted", booted.Format("2006-01-02 15:04:05"))
+	}
+	add("Vendor", info["vendor"])
+	add("Product", info["product"])
+	add("BIOS", info["bios"])
+
+	// Target labels from Prometheus
+	for _, name := range slices.Sorted(maps.Keys(chart.NodeRef.Labels)) {
+		add(name, chart.NodeRef.Labels[name])
+	}
+
+	if len(rows) == 0 {
+		return "No node info available"
+	}
+
+	return NewWrapTable().
+		MaxHeight(height).
+		Headers("Field", "Value").
+		Rows(rows...).
+		Render()
+}
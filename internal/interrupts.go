@@ -0,0 +1,177 @@
+package promtop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Interrupts is how a node's hardware interrupts and softirqs are spread over its CPUs
+type Interrupts struct {
+	CPUs    []string          // Sorted by CPU number
+	Sources []InterruptSource // Busiest first
+}
+
+// InterruptSource is an interrupt line or softirq type and its rate on each CPU
+type InterruptSource struct {
+	Name   string
+	Kind   string             // "irq" or "softirq"
+	PerCPU map[string]float64 // Interrupts per second keyed by CPU
+	Total  float64            // Interrupts per second over all CPUs
+}
+
+// Interrupt counters behind the interrupts chart
+// Softirqs come from the per-CPU softirqs collector, node_softirqs_total from /proc/stat has no CPU label
+const (
+	interruptsMetric = "node_interrupts_total"
+	softirqsMetric   = "node_softirqs_functions_total"
+)
+
+// A source busier than this, in interrupts per second, handled almost entirely by one CPU needs attention
+const (
+	interruptBusyRate    = 1000.0
+	interruptSkewPercent = 90.0
+)
+
+// buildInterrupts turns the per-series rates of the interrupt counters into one entry per source
+func buildInterrupts(irqs, softirqs []sample) Interrupts {
+	sources := make(map[string]*InterruptSource)
+	cpus := make(map[string]bool)
+	add := func(kind, name string, s sample) {
+		key := kind + " " + name
+		if sources[key] == nil {
+			sources[key] = &InterruptSource{Name: name, Kind: kind, PerCPU: make(map[string]float64)}
+		}
+		cpu := s.labels["cpu"]
+		cpus[cpu] = true
+		sources[key].PerCPU[cpu] += s.value
+		sources[key].Total += s.value
+	}
+
+	for _, s := range irqs {
+		// type is the IRQ number or a name such as LOC, devices the drivers on numbered lines
+		name := s.labels["type"]
+		if devices := s.labels["devices"]; devices != "" {
+			name += " " + devices
+		} else if info := s.labels["info"]; info != "" {
+			name += " " + info
+		}
+		add("irq", name, s)
+	}
+	for _, s := range softirqs {
+		add("softirq", s.labels["type"], s)
+	}
+
+	var interrupts Interrupts
+	for cpu := range cpus {
+		interrupts.CPUs = append(interrupts.CPUs, cpu)
+	}
+	sort.Slice(interrupts.CPUs, func(i, j int) bool {
+		return numericLess(interrupts.CPUs[i], interrupts.CPUs[j])
+	})
+	for _, source := range sources {
+		if source.Total > 0 {
+			interrupts.Sources = append(interrupts.Sources, *source)
+		}
+	}
+	sort.Slice(interrupts.Sources, func(i, j int) bool {
+		a, b := interrupts.Sources[i], interrupts.Sources[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Name < b.Name
+	})
+	return interrupts
+}
+
+// busiestShare returns the share of the source's interrupts handled by its busiest CPU as a percentage
+func (s InterruptSource) busiestShare() float64 {
+	if s.Total <= 0 {
+		return 0
+	}
+	busiest := 0.0
+	for _, rate := range s.PerCPU {
+		busiest = max(busiest, rate)
+	}
+	return busiest / s.Total * 100
+}
+
+// status is a warning for a busy source handled almost entirely by one CPU
+func (s InterruptSource) status(cpus int) status {
+	if cpus > 1 && s.Total >= interruptBusyRate && s.busiestShare() >= interruptSkewPercent {
+		return statusWarning
+	}
+	return statusOK
+}
+
+// heatBlocks shade heatmap cells from none to the row's busiest, so an idle CPU stays blank
+var heatBlocks = []rune(" ░▒▓█")
+
+// heatmap draws one cell per value, scaled to the largest value
+func heatmap(values []float64) string {
+	busiest := 0.0
+	for _, v := range values {
+		busiest = max(busiest, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if busiest > 0 && v > 0 {
+			// Any activity at all gets at least the lightest shade
+			level = max(int(v/busiest*float64(len(heatBlocks)-1)), 1)
+		}
+		b.WriteRune(heatBlocks[level])
+	}
+	return b.String()
+}
+
+// renderInterrupts renders the interrupts chart: a heatmap of each source's rate over the CPUs, busiest sources first
+func renderInterrupts(chart Chart, width, height int) string {
+	if chart.InterruptData == nil {
+		return "Waiting for data..."
+	}
+	interrupts := *chart.InterruptData
+	if len(interrupts.Sources) == 0 {
+		return "No interrupt metrics available on this node, the interrupts and softirqs collectors may be disabled"
+	}
+
+	// Leave room for the other columns and the table borders, one cell per CPU
+	heatWidth := max(width-60, 1)
+	cpus := interrupts.CPUs
+	clipped := len(cpus) > heatWidth
+	if clipped {
+		cpus = cpus[:heatWidth]
+	}
+
+	rows := [][]string{}
+	for _, source := range interrupts.Sources {
+		// Each row is scaled to its own busiest CPU, so uneven spreading stands out whatever the rate
+		values := make([]float64, len(cpus))
+		for i, cpu := range cpus {
+			values[i] = source.PerCPU[cpu]
+		}
+		st := source.status(len(interrupts.CPUs))
+		name := source.Name
+		if source.Kind == "softirq" {
+			name = "softirq " + name
+		}
+		rows = append(rows, []string{
+			name,
+			fmt.Sprintf("%.0f/s", source.Total),
+			st.render(fmt.Sprintf("%.0f%%", source.busiestShare())),
+			st.render(heatmap(values)),
+		})
+	}
+
+	header := fmt.Sprintf("CPUs %s-%s", cpus[0], cpus[len(cpus)-1])
+	if clipped {
+		header += fmt.Sprintf(" of %d", len(interrupts.CPUs))
+	}
+
+	return NewWrapTable().
+		MaxHeight(height).
+		Headers("Source", "Rate", "Top CPU", header).
+		Rows(rows...).
+		Render()
+}
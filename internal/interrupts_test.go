@@ -0,0 +1,47 @@
+package promtop
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildInterrupts(t *testing.T) {
+	irq := func(cpu, irqType, devices string, rate float64) sample {
+		return sample{labels: map[string]string{"cpu": cpu, "type": irqType, "devices": devices}, value: rate}
+	}
+	// An NVMe queue pinned to CPU 0 next to timer interrupts spread over every CPU
+	interrupts := buildInterrupts([]sample{
+		irq("0", "130", "nvme0q1", 1900),
+		irq("1", "130", "nvme0q1", 100),
+		irq("0", "LOC", "", 100),
+		irq("1", "LOC", "", 100),
+		irq("2", "LOC", "", 100),
+		irq("10", "LOC", "", 100),
+		irq("0", "NMI", "", 0),
+	}, []sample{
+		{labels: map[string]string{"cpu": "0", "type": "NET_RX"}, value: 50},
+	})
+
+	if want := []string{"0", "1", "2", "10"}; !slices.Equal(interrupts.CPUs, want) {
+		t.Errorf("CPUs = %v, want %v", interrupts.CPUs, want)
+	}
+	var names []string
+	for _, source := range interrupts.Sources {
+		names = append(names, source.Name)
+	}
+	// Busiest first, idle sources left out
+	if want := []string{"130 nvme0q1", "LOC", "NET_RX"}; !slices.Equal(names, want) {
+		t.Fatalf("sources = %v, want %v", names, want)
+	}
+
+	nvme, timer := interrupts.Sources[0], interrupts.Sources[1]
+	if nvme.PerCPU["0"] != 1900 || nvme.Total != 2000 {
+		t.Errorf("nvme = %+v, want 1900 of 2000/s on CPU 0", nvme)
+	}
+	if st := nvme.status(len(interrupts.CPUs)); st != statusWarning {
+		t.Errorf("pinned source status = %v, want warning", st)
+	}
+	if st := timer.status(len(interrupts.CPUs)); st != statusOK {
+		t.Errorf("balanced source status = %v, want ok", st)
+	}
+}
@@ -0,0 +1,120 @@
+package promtop
+
+import (
+	"fmt"
+	"strings"
+)
+
+// kernelMetrics are the gauges behind the kernel resources chart
+// Their values are keyed by the metric name without the node_ prefix
+var kernelMetrics = []string{
+	"node_filefd_allocated",
+	"node_filefd_maximum",
+	"node_nf_conntrack_entries",
+	"node_nf_conntrack_entries_limit",
+	"node_entropy_available_bits",
+	"node_entropy_pool_size_bits",
+	"node_sockstat_sockets_used",
+	"node_sockstat_TCP_inuse",
+	"node_sockstat_TCP_orphan",
+	"node_sockstat_TCP_tw",
+	"node_processes_pids",
+	"node_processes_max_processes",
+	"node_processes_threads",
+	"node_processes_max_threads",
+}
+
+// Use of a kernel limit that needs attention, as a percentage of the limit
+const (
+	kernelWarningPercent  = 80.0
+	kernelCriticalPercent = 95.0
+)
+
+// Entropy below this many bits can make reads from /dev/random block on older kernels
+const entropyWarningBits = 200
+
+// buildKernel keys the kernel resource gauges, given by metric name, the way the chart reads them
+func buildKernel(values map[string]float64) map[string]float64 {
+	kernel := make(map[string]float64, len(values))
+	for name, value := range values {
+		kernel[strings.TrimPrefix(name, "node_")] = value
+	}
+	return kernel
+}
+
+// renderKernel renders the kernel resources chart: each resource as a percentage of its limit
+func renderKernel(chart Chart, width, height int) string {
+	if chart.KernelData == nil {
+		return "Waiting for data..."
+	}
+	data := chart.KernelData
+
+	// Leave room for the other columns and the table borders
+	barWidth := min(max(width-60, 5), 30)
+	bar := func(percent float64) string {
+		filled := min(max(int(percent/100*float64(barWidth)), 0), barWidth)
+		return strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+	}
+
+	rows := [][]string{}
+	limited := func(label, used, limit string) {
+		value, ok := data[used]
+		if !ok {
+			return
+		}
+		limitValue, ok := data[limit]
+		if !ok || limitValue <= 0 {
+			rows = append(rows, []string{label, fmt.Sprintf("%.0f", value), "", "", ""})
+			return
+		}
+		percent := value / limitValue * 100
+		st := statusFor(percent, kernelWarningPercent, kernelCriticalPercent)
+		rows = append(rows, []string{
+			label,
+			st.render(fmt.Sprintf("%.0f", value)),
+			fmt.Sprintf("%.0f", limitValue),
+			st.render(fmt.Sprintf("%.1f%%", percent)),
+			st.render(bar(percent)),
+		})
+	}
+	count := func(label, name string) {
+		if value, ok := data[name]; ok {
+			rows = append(rows, []string{label, fmt.Sprintf("%.0f", value), "", "", ""})
+		}
+	}
+
+	limited("File descriptors", "filefd_allocated", "filefd_maximum")
+	limited("Conntrack entries", "nf_conntrack_entries", "nf_conntrack_entries_limit")
+	limited("Processes", "processes_pids", "processes_max_processes")
+	limited("Threads", "processes_threads", "processes_max_threads")
+
+	// Entropy runs out from the bottom, so it is shown as what is left
+	if bits, ok := data["entropy_available_bits"]; ok {
+		st := statusOK
+		if bits < entropyWarningBits {
+			st = statusWarning
+		}
+		row := []string{"Entropy (available)", st.render(fmt.Sprintf("%.0f bits", bits)), "", "", ""}
+		if pool, ok := data["entropy_pool_size_bits"]; ok && pool > 0 {
+			row[2] = fmt.Sprintf("%.0f bits", pool)
+			row[3] = st.render(fmt.Sprintf("%.1f%%", bits/pool*100))
+			row[4] = st.render(bar(bits / pool * 100))
+		}
+		rows = append(rows, row)
+	}
+
+	count("Sockets", "sockstat_sockets_used")
+	count("TCP in use", "sockstat_TCP_inuse")
+	count("TCP orphans", "sockstat_TCP_orphan")
+	count("TCP TIME_WAIT", "sockstat_TCP_tw")
+
+	if len(rows) == 0 {
+		return "No kernel resource metrics available"
+	}
+
+	return NewWrapTable().
+		MaxHeight(height).
+		Headers("Resource", "Used", "Limit", "% of limit", "").
+		Rows(rows...).
+		Render()
+}
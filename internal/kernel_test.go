@@ -0,0 +1,50 @@
+package promtop
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderKernel(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]float64 // Gauges keyed by metric name
+		want   []string
+	}{
+		{
+			name:   "percent of limit",
+			values: map[string]float64{"node_nf_conntrack_entries": 131072, "node_nf_conntrack_entries_limit": 262144},
+			want:   []string{"Conntrack entries", "131072", "262144", "50.0%"},
+		},
+		{
+			name:   "near exhaustion",
+			values: map[string]float64{"node_filefd_allocated": 1000, "node_filefd_maximum": 1024},
+			want:   []string{"File descriptors", "97.7%"},
+		},
+		{
+			name:   "no limit published",
+			values: map[string]float64{"node_processes_pids": 412},
+			want:   []string{"Processes", "412"},
+		},
+		{
+			name:   "entropy left in the pool",
+			values: map[string]float64{"node_entropy_available_bits": 128, "node_entropy_pool_size_bits": 256},
+			want:   []string{"Entropy (available)", "128 bits", "50.0%"},
+		},
+		{
+			name:   "nothing published",
+			values: map[string]float64{},
+			want:   []string{"No kernel resource metrics available"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderKernel(Chart{KernelData: buildKernel(tt.values)}, 120, 20)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("renderKernel() is missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package promtop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap holds the key bindings for each mode of the dashboard
+type KeyMap struct {
+	Dashboard DashboardKeyMap
+	Modal     ModalKeyMap
+}
+
+// DashboardKeyMap holds the bindings used while browsing panes
+type DashboardKeyMap struct {
+	NewPane   key.Binding
+	AddToPane key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	First     key.Binding
+	Last      key.Binding
+	PrevTab   key.Binding
+	NextTab   key.Binding
+	Remove    key.Binding
+	Expand    key.Binding // Show or hide the detailed view of a chart
+	Help      key.Binding
+	Quit      key.Binding
+}
+
+// ModalKeyMap holds the bindings used in the node selection modal
+type ModalKeyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	First     key.Binding
+	Last      key.Binding
+	PageDown  key.Binding
+	PageUp    key.Binding
+	Search    key.Binding
+	Accept    key.Binding    // Stop typing the search and keep the filter
+	NextMatch key.Binding    // Move down while typing a search
+	PrevMatch key.Binding    // Move up while typing a search
+	Toggle    key.Binding    // Collapse or expand a source or group
+	Charts    []ChartBinding // One binding per chart type
+	AllCharts key.Binding
+	Cancel    key.Binding
+	Help      key.Binding
+	Quit      key.Binding
+}
+
+// ChartBinding adds a chart of the given type for the selected node
+type ChartBinding struct {
+	ChartType string
+	key.Binding
+}
+
+// DefaultKeyMap returns the built-in key bindings
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Dashboard: DashboardKeyMap{
+			NewPane:   key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new pane")),
+			AddToPane: key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add to pane")),
+			Up:        key.NewBinding(key.WithKeys("k", "up"), key.WithHelp("k/↑", "up")),
+			Down:      key.NewBinding(key.WithKeys("j", "down"), key.WithHelp("j/↓", "down")),
+			Left:      key.NewBinding(key.WithKeys("h", "left"), key.WithHelp("h/←", "left")),
+			Right:     key.NewBinding(key.WithKeys("l", "right"), key.WithHelp("l/→", "right")),
+			First:     key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "first pane")),
+			Last:      key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "last pane")),
+			PrevTab:   key.NewBinding(key.WithKeys("["), key.WithHelp("[", "previous tab")),
+			NextTab:   key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "next tab")),
+			Remove:    key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "remove")),
+			Expand:    key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "details")),
+			Help:      key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+			Quit:      key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+		},
+		Modal: ModalKeyMap{
+			Up:        key.NewBinding(key.WithKeys("k", "up"), key.WithHelp("k/↑", "up")),
+			Down:      key.NewBinding(key.WithKeys("j", "down"), key.WithHelp("j/↓", "down")),
+			First:     key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "first node")),
+			Last:      key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "last node")),
+			PageDown:  key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "page down")),
+			PageUp:    key.NewBinding(key.WithKeys("ctrl+u"), key.WithHelp("ctrl+u", "page up")),
+			Search:    key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+			Accept:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "accept search")),
+			NextMatch: key.NewBinding(key.WithKeys("down", "ctrl+n"), key.WithHelp("↓/ctrl+n", "next match")),
+			PrevMatch: key.NewBinding(key.WithKeys("up", "ctrl+p"), key.WithHelp("↑/ctrl+p", "previous match")),
+			Toggle:    key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "collapse/expand")),
+			Charts: []ChartBinding{
+				{"cpu", key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "CPU"))},
+				{"memory", key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "Memory"))},
+				{"disk", key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "Storage"))},
+				{"network", key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "Network"))},
+				{"info", key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "Info"))},
+				{"load", key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "Load"))},
+				{"pressure", key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "Pressure"))},
+				{"swap", key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "Swap"))},
+				{"sensors", key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "Sensors"))},
+				{"frequency", key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "Frequency"))},
+				{"kernel", key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "Kernel resources"))},
+				{"netstat", key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "Network stack"))},
+				{"diskhealth", key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "Disk health"))},
+				{"systemd", key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Systemd units"))},
+				{"timesync", key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "Time sync"))},
+				{"energy", key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "Energy"))},
+				{"nfs", key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "NFS"))},
+				{"interrupts", key.NewBinding(key.WithKeys("h"), key.WithHelp("h", "Interrupts"))},
+				{"containers", key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "Containers"))},
+			},
+			AllCharts: key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "all charts")),
+			Cancel:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear search/cancel")),
+			Help:      key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+			Quit:      key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+		},
+	}
+}
+
+// keyMap is the active key map, set once at startup
+var keyMap = DefaultKeyMap()
+
+// ShortHelp implements help.KeyMap for the help bar
+func (k DashboardKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.NewPane, k.AddToPane, k.PrevTab, k.NextTab, k.Remove, k.Help, k.Quit}
+}
+
+// FullHelp implements help.KeyMap for the help overlay
+func (k DashboardKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.NewPane, k.AddToPane, k.Remove, k.Expand},
+		{k.Up, k.Down, k.Left, k.Right, k.First, k.Last},
+		{k.PrevTab, k.NextTab, k.Help, k.Quit},
+	}
+}
+
+// ShortHelp implements help.KeyMap for the help bar
+func (k ModalKeyMap) ShortHelp() []key.Binding {
+	bindings := make([]key.Binding, 0, len(k.Charts)+3)
+	for _, chart := range k.Charts {
+		bindings = append(bindings, chart.Binding)
+	}
+	return append(bindings, k.AllCharts, k.Search, k.Cancel, k.Help)
+}
+
+// FullHelp implements help.KeyMap for the help overlay
+func (k ModalKeyMap) FullHelp() [][]key.Binding {
+	charts := make([]key.Binding, 0, len(k.Charts)+1)
+	for _, chart := range k.Charts {
+		charts = append(charts, chart.Binding)
+	}
+	return [][]key.Binding{
+		{k.Up, k.Down, k.First, k.Last, k.PageDown, k.PageUp, k.Toggle},
+		append(charts, k.AllCharts),
+		{k.Search, k.Accept, k.NextMatch, k.PrevMatch},
+		{k.Cancel, k.Help, k.Quit},
+	}
+}
+
+// bindings returns the dashboard bindings keyed by their config name
+func (k *DashboardKeyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"new_pane":     &k.NewPane,
+		"add_to_pane":  &k.AddToPane,
+		"up":           &k.Up,
+		"down":         &k.Down,
+		"left":         &k.Left,
+		"right":        &k.Right,
+		"first":        &k.First,
+		"last":         &k.Last,
+		"previous_tab": &k.PrevTab,
+		"next_tab":     &k.NextTab,
+		"remove":       &k.Remove,
+		"expand":       &k.Expand,
+		"help":         &k.Help,
+		"quit":         &k.Quit,
+	}
+}
+
+// bindings returns the modal bindings keyed by their config name
+// Chart bindings are named after their chart type
+func (k *ModalKeyMap) bindings() map[string]*key.Binding {
+	b := map[string]*key.Binding{
+		"up":         &k.Up,
+		"down":       &k.Down,
+		"first":      &k.First,
+		"last":       &k.Last,
+		"page_down":  &k.PageDown,
+		"page_up":    &k.PageUp,
+		"search":     &k.Search,
+		"accept":     &k.Accept,
+		"next_match": &k.NextMatch,
+		"prev_match": &k.PrevMatch,
+		"toggle":     &k.Toggle,
+		"all_charts": &k.AllCharts,
+		"cancel":     &k.Cancel,
+		"help":       &k.Help,
+		"quit":       &k.Quit,
+	}
+	for i := range k.Charts {
+		b[k.Charts[i].ChartType] = &k.Charts[i].Binding
+	}
+	return b
+}
+
+// applyKeys overrides bindings with the keys set in the config
+// Overrides are keyed by mode ("dashboard" or "modal") then binding name
+func (k *KeyMap) applyKeys(overrides map[string]map[string][]string) error {
+	modes := map[string]map[string]*key.Binding{
+		"dashboard": k.Dashboard.bindings(),
+		"modal":     k.Modal.bindings(),
+	}
+	for mode, names := range overrides {
+		bindings, ok := modes[mode]
+		if !ok {
+			return fmt.Errorf("unknown key mode: %s", mode)
+		}
+		for name, keys := range names {
+			binding, ok := bindings[name]
+			if !ok {
+				return fmt.Errorf("unknown %s key binding: %s", mode, name)
+			}
+			if len(keys) == 0 {
+				return fmt.Errorf("%s key binding %s has no keys", mode, name)
+			}
+			binding.SetKeys(keys...)
+			binding.SetHelp(strings.Join(keys, "/"), binding.Help().Desc)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package promtop
+
+import "fmt"
+
+// loadMetrics are the gauges behind the load chart
+// Their values are keyed by the metric name without the node_ prefix
+var loadMetrics = []string{
+	"node_load1",
+	"node_load5",
+	"node_load15",
+	"node_procs_running",
+	"node_procs_blocked",
+}
+
+// Load per core above 1 means tasks are queueing for a CPU
+const (
+	loadWarningPerCore  = 1.0
+	loadCriticalPerCore = 2.0
+)
+
+// renderLoad renders the load chart: load averages per core with their trend, then the run queue
+func renderLoad(chart Chart, width, height int) string {
+	if len(chart.LoadData) == 0 {
+		return "Waiting for data..."
+	}
+
+	latest := func(name string) (float64, bool) {
+		history := chart.LoadData[name]
+		if len(history) == 0 {
+			return 0, false
+		}
+		return history[len(history)-1], true
+	}
+
+	cores, _ := latest("cores")
+	cores = max(cores, 1)
+
+	// Leave room for the other columns and the table borders
+	trendWidth := max(width-40, 0)
+
+	rows := [][]string{}
+	for _, avg := range []struct{ name, label string }{
+		{"load1", "Load 1m"},
+		{"load5", "Load 5m"},
+		{"load15", "Load 15m"},
+	} {
+		value, ok := latest(avg.name)
+		if !ok {
+			continue
+		}
+		perCore := value / cores
+		st := statusFor(perCore, loadWarningPerCore, loadCriticalPerCore)
+
+		// Scale the trend so a full bar is one task per core
+		perCoreHistory := make([]float64, len(chart.LoadData[avg.name]))
+		for i, v := range chart.LoadData[avg.name] {
+			perCoreHistory[i] = v / cores
+		}
+		rows = append(rows, []string{
+			avg.label,
+			st.render(fmt.Sprintf("%.2f", value)),
+			st.render(fmt.Sprintf("%.2f", perCore)),
+			st.render(sparkline(perCoreHistory, trendWidth, loadWarningPerCore)),
+		})
+	}
+
+	if running, ok := latest("procs_running"); ok {
+		// The running count includes the tasks on a CPU, more than one per core are waiting
+		st := statusFor(running/cores, loadWarningPerCore, loadCriticalPerCore)
+		rows = append(rows, []string{
+			"Running",
+			st.render(fmt.Sprintf("%.0f", running)),
+			st.render(fmt.Sprintf("%.2f", running/cores)),
+			sparkline(chart.LoadData["procs_running"], trendWidth, cores),
+		})
+	}
+	if blocked, ok := latest("procs_blocked"); ok {
+		st := statusOK
+		if blocked > 0 {
+			st = statusWarning
+		}
+		rows = append(rows, []string{
+			"Blocked",
+			st.render(fmt.Sprintf("%.0f", blocked)),
+			"",
+			sparkline(chart.LoadData["procs_blocked"], trendWidth, 1),
+		})
+	}
+	if waiting, ok := latest("sched_waiting"); ok {
+		// Seconds spent waiting per second, so 1.0 per core means a task always waiting
+		st := statusFor(waiting/cores, loadWarningPerCore, loadCriticalPerCore)
+		rows = append(rows, []string{
+			"Run queue wait",
+			st.render(fmt.Sprintf("%.0f ms/s", waiting*1000)),
+			st.render(fmt.Sprintf("%.0f ms/s", waiting/cores*1000)),
+			sparkline(chart.LoadData["sched_waiting"], trendWidth, cores),
+		})
+	}
+
+	if len(rows) == 0 {
+		return "No load metrics available"
+	}
+
+	t := NewWrapTable().
+		MaxHeight(height).
+		Headers("Metric", "Value", "Per core", "Trend").
+		Rows(rows...)
+
+	return fmt.Sprintf("%s\n%.0f cores", t.Render(), cores)
+}
@@ -0,0 +1,66 @@
+package promtop
+
+import "fmt"
+
+// memoryDetailMetrics are the /proc/meminfo fields shown in the detailed memory view, keyed by their name in the memory data
+// They are Linux only, HugePages_Total and HugePages_Free are page counts rather than bytes
+var memoryDetailMetrics = map[string]string{
+	"slab":               "node_memory_Slab_bytes",
+	"slab_reclaimable":   "node_memory_SReclaimable_bytes",
+	"slab_unreclaimable": "node_memory_SUnreclaim_bytes",
+	"shmem":              "node_memory_Shmem_bytes",
+	"dirty":              "node_memory_Dirty_bytes",
+	"writeback":          "node_memory_Writeback_bytes",
+	"anon_pages":         "node_memory_AnonPages_bytes",
+	"mapped":             "node_memory_Mapped_bytes",
+	"page_tables":        "node_memory_PageTables_bytes",
+	"hugepages_total":    "node_memory_HugePages_Total",
+	"hugepages_free":     "node_memory_HugePages_Free",
+	"hugepage_size":      "node_memory_Hugepagesize_bytes",
+	"committed":          "node_memory_Committed_AS_bytes",
+	"commit_limit":       "node_memory_CommitLimit_bytes",
+}
+
+// memoryDetailRows returns the rows of the detailed memory view, explaining where used memory went
+func memoryDetailRows(memory map[string]float64) [][]string {
+	rows := [][]string{}
+	add := func(name, label string) {
+		if value, ok := memory[name]; ok {
+			rows = append(rows, []string{label, formatBytes(value)})
+		}
+	}
+
+	add("anon_pages", "Anonymous")
+	add("mapped", "Mapped")
+	add("shmem", "Shared (shmem)")
+	add("slab", "Slab")
+	add("slab_reclaimable", "  Reclaimable")
+	add("slab_unreclaimable", "  Unreclaimable")
+	add("page_tables", "Page tables")
+	add("dirty", "Dirty")
+	add("writeback", "Writeback")
+
+	if total, ok := memory["hugepages_total"]; ok && total > 0 {
+		free := memory["hugepages_free"]
+		value := fmt.Sprintf("%.0f of %.0f used", total-free, total)
+		if size, ok := memory["hugepage_size"]; ok {
+			value += fmt.Sprintf(" (%s)", formatBytes((total-free)*size))
+		}
+		rows = append(rows, []string{"Huge pages", value})
+	}
+
+	// Committed_AS is what processes have been promised, past CommitLimit the kernel relies on overcommit
+	if committed, ok := memory["committed"]; ok {
+		value := formatBytes(committed)
+		st := statusOK
+		if limit, ok := memory["commit_limit"]; ok && limit > 0 {
+			value += fmt.Sprintf(" of %s limit (%.0f%%)", formatBytes(limit), committed/limit*100)
+			if committed > limit {
+				st = statusWarning
+			}
+		}
+		rows = append(rows, []string{"Committed", st.render(value)})
+	}
+
+	return rows
+}
@@ -0,0 +1,69 @@
+package promtop
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NamesConfig controls how nodes are named in the UI
+type NamesConfig struct {
+	// Aliases map a node (its instance or uname nodename) to a fixed display name
+	Aliases map[string]string `mapstructure:"aliases"`
+
+	// Rewrites are applied in order to names without an alias
+	Rewrites []RewriteConfig `mapstructure:"rewrites"`
+}
+
+// RewriteConfig replaces matches of a regular expression in node names
+// Replace can refer to capture groups, e.g. "$1"
+type RewriteConfig struct {
+	Match   string `mapstructure:"match"`
+	Replace string `mapstructure:"replace"`
+}
+
+// nameRewrite is a compiled RewriteConfig
+type nameRewrite struct {
+	pattern *regexp.Regexp
+	replace string
+}
+
+// nameRewrites are the active rewrites, set once at startup
+var nameRewrites []nameRewrite
+
+// compileRewrites compiles the regular expressions of the rewrites
+func compileRewrites(rewrites []RewriteConfig) ([]nameRewrite, error) {
+	compiled := make([]nameRewrite, 0, len(rewrites))
+	for _, r := range rewrites {
+		pattern, err := regexp.Compile(r.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name rewrite %q: %w", r.Match, err)
+		}
+		compiled = append(compiled, nameRewrite{pattern: pattern, replace: r.Replace})
+	}
+	return compiled, nil
+}
+
+// nodeDisplayName resolves the name shown for a node
+// An alias for the node or its uname nodename wins, otherwise the nodename
+// (or the node itself if unknown) is passed through the rewrites
+func nodeDisplayName(node, nodename string) string {
+	// Config keys are lower-cased when they are read
+	for _, name := range []string{node, nodename} {
+		if alias, ok := config.Names.Aliases[strings.ToLower(name)]; ok && name != "" {
+			return alias
+		}
+	}
+
+	name := node
+	if nodename != "" {
+		name = nodename
+	}
+	for _, r := range nameRewrites {
+		name = r.pattern.ReplaceAllString(name, r.replace)
+	}
+	if name == "" {
+		return node
+	}
+	return name
+}
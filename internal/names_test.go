@@ -0,0 +1,49 @@
+package promtop
+
+import "testing"
+
+func TestNodeDisplayName(t *testing.T) {
+	rewrites, err := compileRewrites([]RewriteConfig{
+		{Match: `\.corp\.example\.com$`, Replace: ""},
+		{Match: `:9100$`, Replace: ""},
+		{Match: `^ip-(\d+)-(\d+)-(\d+)-(\d+)$`, Replace: "$1.$2.$3.$4"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousNames, previousRewrites := config.Names, nameRewrites
+	t.Cleanup(func() { config.Names, nameRewrites = previousNames, previousRewrites })
+	// Aliases are lower-cased when the config is read
+	config.Names = NamesConfig{Aliases: map[string]string{
+		"10.2.3.17:9100": "billing-db",
+		"db-02":          "reporting-db",
+	}}
+	nameRewrites = rewrites
+
+	tests := []struct {
+		name     string
+		node     string
+		nodename string
+		want     string
+	}{
+		{name: "address without nodename", node: "10.0.0.5:9100", want: "10.0.0.5"},
+		{name: "nodename", node: "10.0.0.6:9100", nodename: "web-01.corp.example.com", want: "web-01"},
+		{name: "capture groups", node: "10.0.0.7:9100", nodename: "ip-10-0-0-7", want: "10.0.0.7"},
+		{name: "alias by address", node: "10.2.3.17:9100", nodename: "db-01.corp.example.com", want: "billing-db"},
+		{name: "alias by nodename", node: "10.2.3.18:9100", nodename: "DB-02", want: "reporting-db"},
+		{name: "rewritten to nothing", node: "10.0.0.8:9100", nodename: ".corp.example.com", want: "10.0.0.8:9100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeDisplayName(tt.node, tt.nodename); got != tt.want {
+				t.Errorf("nodeDisplayName(%q, %q) = %q, want %q", tt.node, tt.nodename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileRewrites(t *testing.T) {
+	if _, err := compileRewrites([]RewriteConfig{{Match: `(unclosed`}}); err == nil {
+		t.Error("compileRewrites() accepted an invalid regular expression")
+	}
+}
@@ -0,0 +1,158 @@
+package promtop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NetStack is the health of a node's TCP/IP stack
+type NetStack struct {
+	Values  map[string]float64 // Rates per second keyed by netstatRateMetrics name, plus established connections
+	Softnet []SoftnetCPU       // One per CPU, sorted by CPU number
+}
+
+// SoftnetCPU is the packet processing backlog of a CPU, in events per second
+type SoftnetCPU struct {
+	CPU       string
+	Processed float64 // Packets processed
+	Dropped   float64 // Packets dropped because the backlog queue was full
+	Squeezed  float64 // Times processing stopped with work left, because its budget ran out
+}
+
+// netstatRateMetrics are the netstat counters behind the network stack chart, keyed by the name of their rate
+var netstatRateMetrics = map[string]string{
+	"active_opens":      "node_netstat_Tcp_ActiveOpens",        // Connections opened from this node
+	"passive_opens":     "node_netstat_Tcp_PassiveOpens",       // Connections accepted by this node
+	"out_segs":          "node_netstat_Tcp_OutSegs",            // TCP segments sent
+	"retrans_segs":      "node_netstat_Tcp_RetransSegs",        // TCP segments sent again
+	"listen_overflows":  "node_netstat_TcpExt_ListenOverflows", // Connections refused because an accept queue was full
+	"listen_drops":      "node_netstat_TcpExt_ListenDrops",     // Connections dropped while listening, including overflows
+	"udp_rcvbuf_errors": "node_netstat_Udp_RcvbufErrors",       // UDP datagrams dropped because a receive buffer was full
+}
+
+// establishedMetric is the gauge of open TCP connections
+const establishedMetric = "node_netstat_Tcp_CurrEstab"
+
+// Softnet counters behind the network stack chart
+const (
+	softnetProcessedMetric = "node_softnet_processed_total"
+	softnetDroppedMetric   = "node_softnet_dropped_total"
+	softnetSqueezedMetric  = "node_softnet_times_squeezed_total"
+)
+
+// Retransmitted segments that need attention, as a percentage of segments sent
+const (
+	retransWarningPercent  = 1.0
+	retransCriticalPercent = 5.0
+)
+
+// buildSoftnet joins the per-CPU softnet rates into one entry per CPU
+func buildSoftnet(processed, dropped, squeezed []sample) []SoftnetCPU {
+	byCPU := make(map[string]*SoftnetCPU)
+	cpu := func(s sample) *SoftnetCPU {
+		name := s.labels["cpu"]
+		if byCPU[name] == nil {
+			byCPU[name] = &SoftnetCPU{CPU: name}
+		}
+		return byCPU[name]
+	}
+	for _, s := range processed {
+		cpu(s).Processed = s.value
+	}
+	for _, s := range dropped {
+		cpu(s).Dropped = s.value
+	}
+	for _, s := range squeezed {
+		cpu(s).Squeezed = s.value
+	}
+
+	softnet := make([]SoftnetCPU, 0, len(byCPU))
+	for _, c := range byCPU {
+		softnet = append(softnet, *c)
+	}
+	sort.Slice(softnet, func(i, j int) bool {
+		return numericLess(softnet[i].CPU, softnet[j].CPU)
+	})
+	return softnet
+}
+
+// renderNetStack renders the network stack chart: TCP and UDP health, then the softnet backlog per CPU
+func renderNetStack(chart Chart, height int) string {
+	if chart.NetStackData == nil {
+		return "Waiting for data..."
+	}
+	stack := *chart.NetStackData
+	if len(stack.Values) == 0 && len(stack.Softnet) == 0 {
+		return "Network stack metrics are not available on this node"
+	}
+
+	// Any drop is worth knowing about
+	dropStatus := func(rate float64) status {
+		if rate > 0 {
+			return statusWarning
+		}
+		return statusOK
+	}
+
+	rows := [][]string{}
+	rate := func(label, name string, st func(float64) status) {
+		value, ok := stack.Values[name]
+		if !ok {
+			return
+		}
+		s := statusOK
+		if st != nil {
+			s = st(value)
+		}
+		rows = append(rows, []string{label, s.render(fmt.Sprintf("%.2f/s", value))})
+	}
+
+	if established, ok := stack.Values["established"]; ok {
+		rows = append(rows, []string{"TCP established", fmt.Sprintf("%.0f", established)})
+	}
+	rate("TCP active opens", "active_opens", nil)
+	rate("TCP passive opens", "passive_opens", nil)
+	rate("TCP segments sent", "out_segs", nil)
+	if retrans, ok := stack.Values["retrans_segs"]; ok {
+		value := fmt.Sprintf("%.2f/s", retrans)
+		st := statusOK
+		if out := stack.Values["out_segs"]; out > 0 {
+			percent := retrans / out * 100
+			st = statusFor(percent, retransWarningPercent, retransCriticalPercent)
+			value += fmt.Sprintf(" (%.2f%% of sent)", percent)
+		}
+		rows = append(rows, []string{"TCP retransmits", st.render(value)})
+	}
+	rate("Listen overflows", "listen_overflows", dropStatus)
+	rate("Listen drops", "listen_drops", dropStatus)
+	rate("UDP receive buffer errors", "udp_rcvbuf_errors", dropStatus)
+
+	var b strings.Builder
+	if len(rows) > 0 {
+		b.WriteString(NewWrapTable().
+			MaxHeight(height).
+			Headers("Metric", "Value").
+			Rows(rows...).
+			Render())
+		b.WriteString("\n")
+	}
+
+	if len(stack.Softnet) > 0 {
+		softnetRows := [][]string{}
+		for _, c := range stack.Softnet {
+			softnetRows = append(softnetRows, []string{
+				c.CPU,
+				fmt.Sprintf("%.0f/s", c.Processed),
+				dropStatus(c.Dropped).render(fmt.Sprintf("%.2f/s", c.Dropped)),
+				dropStatus(c.Squeezed).render(fmt.Sprintf("%.2f/s", c.Squeezed)),
+			})
+		}
+		b.WriteString(NewWrapTable().
+			MaxHeight(max(height-len(rows)-4, 5)).
+			Headers("CPU", "Processed", "Dropped", "Squeezed").
+			Rows(softnetRows...).
+			Render())
+	}
+	return b.String()
+}
@@ -0,0 +1,198 @@
+package promtop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NetworkInterface is the state and throughput of a network interface
+type NetworkInterface struct {
+	Device     string
+	OperState  string  // Kernel operational state from node_network_info, e.g. "up", "down" or "lowerlayerdown"
+	Up         bool    // Operational state is up
+	Carrier    float64 // 1 with a link, 0 without, -1 if not readable (the interface is administratively down)
+	Speed      float64 // Link speed in bytes per second, 0 if not published (virtual interfaces)
+	MTU        float64
+	Receive    float64 // Bytes received per second
+	Transmit   float64 // Bytes sent per second
+	Slaves     float64 // Bond slaves, 0 if not a bond
+	Active     float64 // Bond slaves that are up
+	HasReading bool    // Receive and transmit rates are available
+}
+
+// networkMetrics are the gauges behind the network chart
+var networkMetrics = []string{
+	"node_network_info",
+	"node_network_up",
+	"node_network_carrier",
+	"node_network_speed_bytes",
+	"node_network_mtu_bytes",
+	"node_bonding_slaves",
+	"node_bonding_active",
+}
+
+// Throughput counters behind the network chart
+const (
+	networkReceiveMetric  = "node_network_receive_bytes_total"
+	networkTransmitMetric = "node_network_transmit_bytes_total"
+)
+
+// Link utilisation that needs attention, as a percentage of link speed in the busier direction
+const (
+	linkWarningPercent  = 70.0
+	linkCriticalPercent = 90.0
+)
+
+// buildNetwork joins the interface gauges and throughput rates into one entry per interface, sorted by device
+func buildNetwork(samples map[string][]sample, receive, transmit []sample) []NetworkInterface {
+	byDevice := make(map[string]*NetworkInterface)
+	device := func(name string) *NetworkInterface {
+		if byDevice[name] == nil {
+			byDevice[name] = &NetworkInterface{Device: name, Carrier: -1}
+		}
+		return byDevice[name]
+	}
+
+	for _, s := range samples["node_network_info"] {
+		device(s.labels["device"]).OperState = s.labels["operstate"]
+	}
+	for _, s := range samples["node_network_up"] {
+		device(s.labels["device"]).Up = s.value == 1
+	}
+	for _, s := range samples["node_network_carrier"] {
+		device(s.labels["device"]).Carrier = s.value
+	}
+	for _, s := range samples["node_network_speed_bytes"] {
+		// Virtual interfaces report -1 or an unknown speed
+		device(s.labels["device"]).Speed = max(s.value, 0)
+	}
+	for _, s := range samples["node_network_mtu_bytes"] {
+		device(s.labels["device"]).MTU = s.value
+	}
+	// Bonding metrics are labelled by the bond's own device name
+	for _, s := range samples["node_bonding_slaves"] {
+		device(s.labels["master"]).Slaves = s.value
+	}
+	for _, s := range samples["node_bonding_active"] {
+		device(s.labels["master"]).Active = s.value
+	}
+	for _, s := range receive {
+		d := device(s.labels["device"])
+		d.Receive = s.value
+		d.HasReading = true
+	}
+	for _, s := range transmit {
+		d := device(s.labels["device"])
+		d.Transmit = s.value
+		d.HasReading = true
+	}
+
+	interfaces := make([]NetworkInterface, 0, len(byDevice))
+	for _, d := range byDevice {
+		interfaces = append(interfaces, *d)
+	}
+	sort.Slice(interfaces, func(i, j int) bool {
+		return interfaces[i].Device < interfaces[j].Device
+	})
+	return interfaces
+}
+
+// utilisation returns the busier direction as a percentage of link speed, false if the speed isn't known
+func (n NetworkInterface) utilisation() (float64, bool) {
+	if n.Speed <= 0 || !n.HasReading {
+		return 0, false
+	}
+	return max(n.Receive, n.Transmit) / n.Speed * 100, true
+}
+
+// state describes the link and how much attention it needs
+func (n NetworkInterface) state() (string, status) {
+	if n.Slaves > 0 {
+		switch {
+		case n.Active == 0:
+			return "bond down", statusCritical
+		case n.Active < n.Slaves:
+			return fmt.Sprintf("degraded %.0f/%.0f", n.Active, n.Slaves), statusWarning
+		}
+	}
+	switch {
+	case n.Up:
+		return "up", statusOK
+	case n.Carrier == 0:
+		// Configured up but without a link, e.g. an unplugged cable
+		return "no carrier", statusCritical
+	case n.OperState == "lowerlayerdown" || n.OperState == "dormant":
+		return n.OperState, statusWarning
+	case n.OperState == "down":
+		return "down", statusWarning
+	case n.OperState != "":
+		// Loopback and tunnels report unknown
+		return n.OperState, statusOK
+	}
+	return "", statusOK
+}
+
+// formatLinkSpeed formats a link speed in bytes per second as bits per second
+func formatLinkSpeed(bytes float64) string {
+	bits := bytes * 8
+	switch {
+	case bits == 0:
+		return ""
+	case bits >= 1e9:
+		return fmt.Sprintf("%g Gb/s", bits/1e9)
+	default:
+		return fmt.Sprintf("%g Mb/s", bits/1e6)
+	}
+}
+
+// renderNetwork renders the network chart: each interface's state and throughput against its link speed
+func renderNetwork(chart Chart, width, height int) string {
+	if chart.NetworkData == nil {
+		return "Waiting for data..."
+	}
+	if len(chart.NetworkData) == 0 {
+		return "No network interfaces available on this node"
+	}
+
+	// Leave room for the other columns and the table borders
+	barWidth := min(max(width-85, 5), 20)
+
+	rows := [][]string{}
+	for _, n := range chart.NetworkData {
+		state, st := n.state()
+		rate := func(bytes float64) string {
+			if !n.HasReading {
+				return ""
+			}
+			return formatBytes(bytes) + "/s"
+		}
+		mtu := ""
+		if n.MTU > 0 {
+			mtu = fmt.Sprintf("%.0f", n.MTU)
+		}
+		util, bar := "", ""
+		if percent, ok := n.utilisation(); ok {
+			ust := statusFor(percent, linkWarningPercent, linkCriticalPercent)
+			filled := min(int(percent/100*float64(barWidth)), barWidth)
+			util = ust.render(fmt.Sprintf("%.1f%%", percent))
+			bar = ust.render(strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled))
+		}
+		rows = append(rows, []string{
+			n.Device,
+			st.render(state),
+			formatLinkSpeed(n.Speed),
+			mtu,
+			rate(n.Receive),
+			rate(n.Transmit),
+			util,
+			bar,
+		})
+	}
+
+	return NewWrapTable().
+		MaxHeight(height).
+		Headers("Interface", "State", "Speed", "MTU", "RX", "TX", "Util", "").
+		Rows(rows...).
+		Render()
+}
@@ -0,0 +1,205 @@
+package promtop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NFSStats is the NFS activity of a node as a client and as a server
+type NFSStats struct {
+	Ops      []NFSOp    // Client and server operations per second by method, busiest first
+	Retrans  float64    // Client RPC retransmissions per second
+	Errors   float64    // Server RPC errors per second
+	MountOps []NFSOp    // Operations per second with their latency, per mount and operation, busiest first
+	Mounts   []NFSMount // Sorted by export
+}
+
+// NFSOp is the rate and latency of an NFS operation
+type NFSOp struct {
+	Side      string // "client" or "server" for Ops, the export for MountOps
+	Operation string
+	Rate      float64 // Operations per second
+	RTT       float64 // Mean seconds from sending a request to its reply, mountstats only
+	Exec      float64 // Mean seconds from queueing a request to its completion, mountstats only
+}
+
+// NFSMount is the traffic of a mounted NFS export
+type NFSMount struct {
+	Export  string
+	Rate    float64 // Operations per second
+	Retrans float64 // Retransmissions per second
+}
+
+// nfsMetrics are the counters behind the NFS chart
+var nfsMetrics = []string{
+	"node_nfs_requests_total",
+	"node_nfs_rpc_retransmissions_total",
+	"node_nfsd_requests_total",
+	"node_nfsd_rpc_errors_total",
+	"node_mountstats_nfs_operations_requests_total",
+	"node_mountstats_nfs_operations_transmissions_total",
+	"node_mountstats_nfs_operations_response_time_seconds_total",
+	"node_mountstats_nfs_operations_request_time_seconds_total",
+}
+
+// NFS round trip times that need attention, in seconds
+const (
+	nfsWarningSeconds  = 0.02
+	nfsCriticalSeconds = 0.1
+)
+
+// buildNFS turns the per-series rates of the NFS counters, keyed by metric name, into the NFS chart
+func buildNFS(rates map[string][]sample) NFSStats {
+	var stats NFSStats
+
+	ops := func(metric, side string) {
+		byMethod := make(map[string]float64)
+		for _, s := range rates[metric] {
+			// Requests are counted per protocol version
+			byMethod[s.labels["method"]] += s.value
+		}
+		for method, rate := range byMethod {
+			if rate > 0 {
+				stats.Ops = append(stats.Ops, NFSOp{Side: side, Operation: method, Rate: rate})
+			}
+		}
+	}
+	ops("node_nfs_requests_total", "client")
+	ops("node_nfsd_requests_total", "server")
+	sortOps(stats.Ops)
+
+	for _, s := range rates["node_nfs_rpc_retransmissions_total"] {
+		stats.Retrans += s.value
+	}
+	for _, s := range rates["node_nfsd_rpc_errors_total"] {
+		stats.Errors += s.value
+	}
+
+	// mountstats series are labelled by export and operation, and by transport and mount address too
+	mountOps := make(map[string]*NFSOp)
+	mounts := make(map[string]*NFSMount)
+	sum := func(metric string, add func(op *NFSOp, mount *NFSMount, value float64)) {
+		for _, s := range rates[metric] {
+			export := s.labels["export"]
+			key := export + " " + s.labels["operation"]
+			if mountOps[key] == nil {
+				mountOps[key] = &NFSOp{Side: export, Operation: s.labels["operation"]}
+			}
+			if mounts[export] == nil {
+				mounts[export] = &NFSMount{Export: export}
+			}
+			add(mountOps[key], mounts[export], s.value)
+		}
+	}
+	sum("node_mountstats_nfs_operations_requests_total", func(op *NFSOp, mount *NFSMount, value float64) {
+		op.Rate += value
+		mount.Rate += value
+	})
+	// A request sent more than once was retransmitted
+	sum("node_mountstats_nfs_operations_transmissions_total", func(op *NFSOp, mount *NFSMount, value float64) {
+		mount.Retrans += value
+	})
+	sum("node_mountstats_nfs_operations_requests_total", func(op *NFSOp, mount *NFSMount, value float64) {
+		mount.Retrans -= value
+	})
+	// Seconds spent per second, divided by operations per second later for the mean per operation
+	sum("node_mountstats_nfs_operations_response_time_seconds_total", func(op *NFSOp, mount *NFSMount, value float64) {
+		op.RTT += value
+	})
+	sum("node_mountstats_nfs_operations_request_time_seconds_total", func(op *NFSOp, mount *NFSMount, value float64) {
+		op.Exec += value
+	})
+
+	for _, op := range mountOps {
+		if op.Rate <= 0 {
+			continue
+		}
+		op.RTT /= op.Rate
+		op.Exec /= op.Rate
+		stats.MountOps = append(stats.MountOps, *op)
+	}
+	sortOps(stats.MountOps)
+	for _, mount := range mounts {
+		mount.Retrans = max(mount.Retrans, 0)
+		stats.Mounts = append(stats.Mounts, *mount)
+	}
+	sort.Slice(stats.Mounts, func(i, j int) bool {
+		return stats.Mounts[i].Export < stats.Mounts[j].Export
+	})
+	return stats
+}
+
+// sortOps orders operations busiest first
+func sortOps(ops []NFSOp) {
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Rate != ops[j].Rate {
+			return ops[i].Rate > ops[j].Rate
+		}
+		return ops[i].Side+ops[i].Operation < ops[j].Side+ops[j].Operation
+	})
+}
+
+// renderNFS renders the NFS chart: RPC health, operations per mount with their latency, then client and server operations
+func renderNFS(chart Chart, height int) string {
+	if chart.NFSData == nil {
+		return "Waiting for data..."
+	}
+	stats := *chart.NFSData
+	if len(stats.Ops) == 0 && len(stats.MountOps) == 0 && len(stats.Mounts) == 0 && stats.Retrans == 0 && stats.Errors == 0 {
+		return "No NFS activity on this node"
+	}
+
+	// Any retransmission or error is worth knowing about
+	rate := func(value float64) string {
+		text := fmt.Sprintf("%.2f/s", value)
+		if value > 0 {
+			return statusWarning.render(text)
+		}
+		return text
+	}
+	millis := func(seconds float64) string {
+		return statusFor(seconds, nfsWarningSeconds, nfsCriticalSeconds).render(fmt.Sprintf("%.1f ms", seconds*1000))
+	}
+
+	var sections []string
+	sections = append(sections, fmt.Sprintf("Client retransmissions %s, server errors %s", rate(stats.Retrans), rate(stats.Errors)))
+
+	if len(stats.Mounts) > 0 {
+		rows := [][]string{}
+		for _, m := range stats.Mounts {
+			rows = append(rows, []string{m.Export, fmt.Sprintf("%.1f/s", m.Rate), rate(m.Retrans)})
+		}
+		sections = append(sections, NewWrapTable().
+			MaxHeight(height).
+			Headers("Mount", "Ops", "Retrans").
+			Rows(rows...).
+			Render())
+	}
+
+	if len(stats.MountOps) > 0 {
+		rows := [][]string{}
+		for _, op := range stats.MountOps {
+			rows = append(rows, []string{op.Side, op.Operation, fmt.Sprintf("%.1f/s", op.Rate), millis(op.RTT), millis(op.Exec)})
+		}
+		sections = append(sections, NewWrapTable().
+			MaxHeight(height).
+			Headers("Mount", "Operation", "Ops", "RTT", "Exec").
+			Rows(rows...).
+			Render())
+	}
+
+	if len(stats.Ops) > 0 {
+		rows := [][]string{}
+		for _, op := range stats.Ops {
+			rows = append(rows, []string{op.Side, op.Operation, fmt.Sprintf("%.1f/s", op.Rate)})
+		}
+		sections = append(sections, NewWrapTable().
+			MaxHeight(height).
+			Headers("Side", "Operation", "Ops").
+			Rows(rows...).
+			Render())
+	}
+
+	return strings.Join(sections, "\n")
+}
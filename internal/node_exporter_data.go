@@ -1,10 +1,10 @@
 package promtop
 
 import (
-	"cmp"
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"net/http"
 	"net/url"
 	"slices"
@@ -17,9 +17,8 @@ import (
 )
 
 type NodeExporterData struct {
-	cpus       [][]*dto.Metric
-	timestamps []time.Time
-	nodes      map[string]*url.URL
+	counters map[string]*counterStore // Counter history per node for rate calculations
+	nodes    map[string]*url.URL
 }
 
 func NewNodeExporterData(urls []*url.URL) (*NodeExporterData, error) {
@@ -105,114 +104,211 @@ func (n *NodeExporterData) GetNodes() []string {
 	return keys
 }
 
-func (n *NodeExporterData) GetCpu(node string) map[string]float64 {
+// GetNodeLabels returns nil as node_exporter targets have no labels of their own
+func (n *NodeExporterData) GetNodeLabels() map[string]map[string]string {
+	return nil
+}
+
+// GetNodeNames returns the nodename each node reports in node_uname_info
+// Nodes that can't be reached are left out so they keep their address as a name
+func (n *NodeExporterData) GetNodeNames() map[string]string {
+	names := make(map[string]string, len(n.nodes))
+	for node := range n.nodes {
+		data, err := n.fetch(node)
+		if err != nil {
+			log.Printf("Failed to read node_uname_info from %s: %v", node, err)
+			continue
+		}
+		for _, metric := range data["node_uname_info"].GetMetric() {
+			if nodename := labelValue(metric, "nodename"); nodename != "" {
+				names[node] = nodename
+			}
+		}
+	}
+	return names
+}
+
+// scrape fetches and parses the metrics exposed by a node
+func (n *NodeExporterData) scrape(node string) map[string]*dto.MetricFamily {
+	data, err := n.fetch(node)
+	if err != nil {
+		log.Fatalln("Error querying node exporter:", err)
+	}
+	return data
+}
+
+// fetch fetches and parses the metrics exposed by a node, returning any error
+func (n *NodeExporterData) fetch(node string) (map[string]*dto.MetricFamily, error) {
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
 	resp, err := client.Get(n.nodes[node].String())
 	if err != nil {
-		log.Fatalln("Error querying node exporter:", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Fatalln("Failed to read response body:", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 	parser := expfmt.TextParser{}
 	data, err := parser.TextToMetricFamilies(strings.NewReader(string(body)))
 	if err != nil {
-		log.Fatalln("Failed to parse metrics:", err)
+		return nil, fmt.Errorf("failed to parse metrics: %w", err)
 	}
+	return data, nil
+}
 
-	// extract cpu idle time metrics
-	currentCpu := slices.DeleteFunc(data["node_cpu_seconds_total"].GetMetric(), func(metric *dto.Metric) bool {
-		var cpu, mode string
-		for _, label := range metric.GetLabel() {
-			if label.GetName() == "cpu" {
-				cpu = label.GetValue()
-			}
-			if label.GetName() == "mode" {
-				mode = label.GetValue()
-			}
+// countersFor returns the counter history for a node, creating it if needed
+func (n *NodeExporterData) countersFor(node string) *counterStore {
+	if n.counters == nil {
+		n.counters = make(map[string]*counterStore)
+	}
+	if n.counters[node] == nil {
+		n.counters[node] = newCounterStore()
+	}
+	return n.counters[node]
+}
+
+// labelValue returns the value of a label on a metric, or "" if it is missing
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
 		}
-		_, err := strconv.Atoi(cpu)
-		return mode != "idle" || err != nil
-	})
-	// clear the slice to free memory
-	clear(currentCpu[len(currentCpu):cap(currentCpu)])
+	}
+	return ""
+}
 
-	// sort by cpu number
-	slices.SortFunc(currentCpu, func(a, b *dto.Metric) int {
-		return cmp.Compare(a.GetCounter().GetValue(), b.GetCounter().GetValue())
-	})
+// gauge returns the value of the first series of a metric
+func gauge(data map[string]*dto.MetricFamily, name string) (float64, bool) {
+	metrics := data[name].GetMetric()
+	if len(metrics) == 0 {
+		return 0, false
+	}
+	return metricValue(metrics[0]), true
+}
 
-	// append the new reading to the readings slice
-	n.cpus = append(n.cpus, currentCpu)
-	n.timestamps = append(n.timestamps, time.Now())
-	// limit the readings slice to MaxCPURecords entries
-	maxRecords := MaxCPURecords()
-	if len(n.cpus) > maxRecords {
-		n.cpus = n.cpus[1:]
-		n.timestamps = n.timestamps[1:]
+// counterRate records the current value of each series of a counter
+// and returns the per-second rate summed over all of them
+// Returns false until there are two readings
+func counterRate(data map[string]*dto.MetricFamily, counters *counterStore, now time.Time, metric string) (float64, bool) {
+	rates := seriesRates(data, counters, now, metric)
+	total := 0.0
+	for _, rate := range rates {
+		total += rate.value
 	}
+	return total, len(rates) > 0
+}
 
-	// calculate the cpu usage rates
-	rates := make(map[string]float64)
-	if len(n.cpus) < 2 {
-		return rates
-	}
-
-	// calculate the interval between the first and last reading
-	interval := n.timestamps[len(n.timestamps)-1].Sub(n.timestamps[0]).Seconds()
-	for cpuIndex := 0; cpuIndex < len(n.cpus[0]); cpuIndex++ {
-		// Get the CPU name from the metric labels
-		var cpuName string
-		for _, label := range n.cpus[0][cpuIndex].GetLabel() {
-			if label.GetName() == "cpu" {
-				cpuName = label.GetValue()
-				break
-			}
+// seriesRates records the current value of each series of a counter and returns their per-second rates
+// Series are left out until they have two readings, and forgotten once they are no longer scraped
+func seriesRates(data map[string]*dto.MetricFamily, counters *counterStore, now time.Time, metric string) []sample {
+	var rates []sample
+	seen := make(map[string]bool)
+	for _, s := range samples(data, metric)[metric] {
+		key := metric + seriesKey(s.labels)
+		seen[key] = true
+		counters.add(key, now, s.value)
+		if rate, ok := counters.rate(key); ok {
+			rates = append(rates, sample{labels: s.labels, value: rate})
 		}
+	}
+	counters.prune(metric, seen)
+	return rates
+}
 
-		// each cpu counter might have been reset so we need to calculate an offset
-		offset := 0.0
-		for readingIndex, reading := range n.cpus {
-			if readingIndex > 0 && reading[cpuIndex].GetCounter().GetValue() < n.cpus[readingIndex-1][cpuIndex].GetCounter().GetValue() {
-				offset += n.cpus[readingIndex-1][cpuIndex].GetCounter().GetValue()
-			}
+// seriesDerivs records the current value of each series of a gauge and returns how fast they change per second
+// Series are left out until they have two readings, and forgotten once they are no longer scraped
+func seriesDerivs(data map[string]*dto.MetricFamily, counters *counterStore, now time.Time, metric string) []sample {
+	var derivs []sample
+	seen := make(map[string]bool)
+	for _, s := range samples(data, metric)[metric] {
+		key := metric + seriesKey(s.labels)
+		seen[key] = true
+		counters.add(key, now, s.value)
+		if deriv, ok := counters.delta(key); ok {
+			derivs = append(derivs, sample{labels: s.labels, value: deriv})
 		}
-		// use the first nad last reading to calculate the cpu usage rate
-		first := n.cpus[0][cpuIndex].GetCounter().GetValue() + offset
-		last := n.cpus[len(n.cpus)-1][cpuIndex].GetCounter().GetValue() + offset
-		// because the times should add up to the interval
-		// we can calculate the cpu usage rate by subtracting the idle time from 100%
-		rates[cpuName] = 100 - 100*(last-first)/interval
 	}
+	counters.prune(metric, seen)
+	return derivs
+}
 
-	return rates
+// seriesKey identifies a series of a metric by its labels, e.g. {cpu="0",mode="idle"}
+func seriesKey(labels map[string]string) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i, name := range slices.Sorted(maps.Keys(labels)) {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, "%s=%q", name, labels[name])
+	}
+	b.WriteString("}")
+	return b.String()
 }
 
-func (n *NodeExporterData) GetMemory(node string) map[string]float64 {
-	client := http.Client{
-		Timeout: 5 * time.Second,
+// samples returns every series of the named metrics, grouped by metric name
+func samples(data map[string]*dto.MetricFamily, names ...string) map[string][]sample {
+	samples := make(map[string][]sample)
+	for _, name := range names {
+		for _, metric := range data[name].GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			samples[name] = append(samples[name], sample{labels: labels, value: metricValue(metric)})
+		}
 	}
-	resp, err := client.Get(n.nodes[node].String())
-	if err != nil {
-		log.Fatalln("Error querying node exporter:", err)
+	return samples
+}
+
+// metricValue returns the value of a gauge, counter or untyped metric
+func metricValue(metric *dto.Metric) float64 {
+	switch {
+	case metric.Gauge != nil:
+		return metric.GetGauge().GetValue()
+	case metric.Counter != nil:
+		return metric.GetCounter().GetValue()
+	default:
+		return metric.GetUntyped().GetValue()
 	}
-	defer resp.Body.Close()
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatalln("Failed to read response body:", err)
+func (n *NodeExporterData) GetCpu(node string) map[string]float64 {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	// record the cpu idle time counters
+	var cpuNames []string
+	for _, metric := range data["node_cpu_seconds_total"].GetMetric() {
+		cpu := labelValue(metric, "cpu")
+		if _, err := strconv.Atoi(cpu); err != nil || labelValue(metric, "mode") != "idle" {
+			continue
+		}
+		counters.add("cpu:"+cpu, now, metric.GetCounter().GetValue())
+		cpuNames = append(cpuNames, cpu)
 	}
-	parser := expfmt.TextParser{}
-	data, err := parser.TextToMetricFamilies(strings.NewReader(string(body)))
-	if err != nil {
-		log.Fatalln("Failed to parse metrics:", err)
+
+	// calculate the cpu usage rates
+	// because the times should add up to the interval
+	// we can calculate the cpu usage rate by subtracting the idle time from 100%
+	rates := make(map[string]float64)
+	for _, cpu := range cpuNames {
+		if idle, ok := counters.rate("cpu:" + cpu); ok {
+			rates[cpu] = 100 - 100*idle
+		}
 	}
 
+	return rates
+}
+
+func (n *NodeExporterData) GetMemory(node string) map[string]float64 {
+	data := n.scrape(node)
+
 	memory := make(map[string]float64)
 
 	// Get total memory (Linux: MemTotal_bytes, macOS: total_bytes)
@@ -281,9 +377,292 @@ func (n *NodeExporterData) GetMemory(node string) map[string]float64 {
 		}
 	}
 
+	// Detailed breakdown (Linux only)
+	for name, metric := range memoryDetailMetrics {
+		if value, ok := gauge(data, metric); ok {
+			memory[name] = value
+		}
+	}
+
 	return memory
 }
 
 func (n *NodeExporterData) GetType() string {
 	return "node_exporter"
 }
+
+func (n *NodeExporterData) GetInfo(node string) map[string]string {
+	data := n.scrape(node)
+
+	series := make(map[string][]map[string]string)
+	values := make(map[string]float64)
+	for _, name := range infoMetrics {
+		for _, metric := range data[name].GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			series[name] = append(series[name], labels)
+			if _, ok := values[name]; !ok {
+				values[name] = metricValue(metric)
+			}
+		}
+	}
+
+	return buildNodeInfo(series, values)
+}
+
+func (n *NodeExporterData) GetLoad(node string) map[string]float64 {
+	data := n.scrape(node)
+
+	load := make(map[string]float64)
+	for _, name := range loadMetrics {
+		if value, ok := gauge(data, name); ok {
+			load[strings.TrimPrefix(name, "node_")] = value
+		}
+	}
+
+	cores := 0
+	for _, metric := range data["node_cpu_seconds_total"].GetMetric() {
+		if labelValue(metric, "mode") == "idle" {
+			cores++
+		}
+	}
+	if cores > 0 {
+		load["cores"] = float64(cores)
+	}
+
+	// Time tasks spent waiting for a CPU, summed over every CPU
+	if waiting, ok := counterRate(data, n.countersFor(node), time.Now(), "node_schedstat_waiting_seconds_total"); ok {
+		load["sched_waiting"] = waiting
+	}
+
+	return load
+}
+
+// GetPressure returns the percentage of time tasks were stalled on each resource
+// Returns nil if the node doesn't expose PSI, and an empty map until there are two readings
+func (n *NodeExporterData) GetPressure(node string) map[string]float64 {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	var pressure map[string]float64
+	for name, metric := range pressureMetrics {
+		value, ok := gauge(data, metric)
+		if !ok {
+			continue
+		}
+		if pressure == nil {
+			pressure = make(map[string]float64)
+		}
+		counters.add("pressure:"+name, now, value)
+		if rate, ok := counters.rate("pressure:" + name); ok {
+			pressure[name] = rate * 100
+		}
+	}
+	return pressure
+}
+
+func (n *NodeExporterData) GetSwap(node string) map[string]float64 {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	values := make(map[string]float64)
+	for _, name := range []string{"node_memory_SwapTotal_bytes", "node_memory_SwapFree_bytes", "node_vmstat_oom_kill"} {
+		if value, ok := gauge(data, name); ok {
+			values[name] = value
+		}
+	}
+	for name, metric := range pagingMetrics {
+		if rate, ok := counterRate(data, counters, now, metric); ok {
+			values[name] = rate
+		}
+	}
+	return buildSwap(values)
+}
+
+// GetSensors returns the node's temperature and fan sensors
+// IPMI readings are included when the URL points at an IPMI exporter
+func (n *NodeExporterData) GetSensors(node string) []Sensor {
+	data := n.scrape(node)
+	return buildSensors(samples(data, slices.Concat(sensorMetrics, ipmiSensorMetrics)...))
+}
+
+func (n *NodeExporterData) GetFrequency(node string) CPUFrequency {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	return buildCPUFrequency(
+		samples(data, frequencyMetrics...),
+		seriesRates(data, counters, now, coreThrottlesMetric),
+		seriesRates(data, counters, now, packageThrottlesMetric),
+	)
+}
+
+func (n *NodeExporterData) GetKernel(node string) map[string]float64 {
+	data := n.scrape(node)
+
+	values := make(map[string]float64)
+	for _, name := range kernelMetrics {
+		if value, ok := gauge(data, name); ok {
+			values[name] = value
+		}
+	}
+	return buildKernel(values)
+}
+
+func (n *NodeExporterData) GetNetStack(node string) NetStack {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	values := make(map[string]float64)
+	if established, ok := gauge(data, establishedMetric); ok {
+		values["established"] = established
+	}
+	for name, metric := range netstatRateMetrics {
+		if rate, ok := counterRate(data, counters, now, metric); ok {
+			values[name] = rate
+		}
+	}
+	return NetStack{
+		Values: values,
+		Softnet: buildSoftnet(
+			seriesRates(data, counters, now, softnetProcessedMetric),
+			seriesRates(data, counters, now, softnetDroppedMetric),
+			seriesRates(data, counters, now, softnetSqueezedMetric),
+		),
+	}
+}
+
+func (n *NodeExporterData) GetNetwork(node string) []NetworkInterface {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	return buildNetwork(
+		samples(data, networkMetrics...),
+		seriesRates(data, counters, now, networkReceiveMetric),
+		seriesRates(data, counters, now, networkTransmitMetric),
+	)
+}
+
+// GetDiskHealth returns the node's RAID arrays and disk error rates
+// SMART readings need smartctl_exporter, which only a Prometheus source finds alongside node_exporter
+func (n *NodeExporterData) GetDiskHealth(node string) DiskHealth {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	errorRates := make(map[string][]sample)
+	for name := range data {
+		if isDiskErrorMetric(name) {
+			errorRates[name] = seriesRates(data, counters, now, name)
+		}
+	}
+	return buildDiskHealth(samples(data, mdMetrics...), seriesDerivs(data, counters, now, mdSyncedMetric), errorRates)
+}
+
+func (n *NodeExporterData) GetSystemd(node string) SystemdUnits {
+	return buildSystemd(samples(n.scrape(node), systemdMetrics...), config.SystemdUnits)
+}
+
+// GetTimeSync returns the clock state of every node, with skew measured against the local clock
+func (n *NodeExporterData) GetTimeSync() []NodeClock {
+	var clocks []NodeClock
+	for _, node := range n.GetNodes() {
+		start := time.Now()
+		data, err := n.fetch(node)
+		end := time.Now()
+		if err != nil {
+			log.Printf("Failed to read clock from %s: %v", node, err)
+			clocks = append(clocks, NodeClock{Node: node})
+			continue
+		}
+
+		values := make(map[string]float64)
+		for _, name := range timexMetrics {
+			if value, ok := gauge(data, name); ok {
+				values[name] = value
+			}
+		}
+		nodeTime, hasSkew := gauge(data, nodeTimeMetric)
+		skew := 0.0
+		if hasSkew {
+			skew = clockSkew(nodeTime, start, end)
+		}
+		clocks = append(clocks, buildClock(node, values, skew, hasSkew))
+	}
+	return clocks
+}
+
+// GetEnergy returns the node's power draw in watts per RAPL domain and package
+// Rates need two readings, so the first scrape of a node with RAPL counters gives an empty map rather than nil
+func (n *NodeExporterData) GetEnergy(node string) map[string]float64 {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	available := false
+	rates := make(map[string][]sample)
+	for domain, metric := range raplMetrics {
+		if _, ok := data[metric]; ok {
+			available = true
+		}
+		rates[domain] = seriesRates(data, counters, now, metric)
+	}
+	if !available {
+		return nil
+	}
+	return buildEnergy(rates)
+}
+
+func (n *NodeExporterData) GetNFS(node string) NFSStats {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	rates := make(map[string][]sample)
+	for _, metric := range nfsMetrics {
+		rates[metric] = seriesRates(data, counters, now, metric)
+	}
+	return buildNFS(rates)
+}
+
+func (n *NodeExporterData) GetTopology(node string) CPUTopology {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	return buildCPUTopology(
+		samples(data, append([]string{"node_cpu_info"}, numaMetrics...)...),
+		seriesRates(data, counters, now, numaMissMetric),
+	)
+}
+
+func (n *NodeExporterData) GetInterrupts(node string) Interrupts {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	return buildInterrupts(
+		seriesRates(data, counters, now, interruptsMetric),
+		seriesRates(data, counters, now, softirqsMetric),
+	)
+}
+
+// GetContainers returns the containers on the node when the URL points at cAdvisor
+func (n *NodeExporterData) GetContainers(node string) Containers {
+	data := n.scrape(node)
+	counters := n.countersFor(node)
+	now := time.Now()
+
+	rates := make(map[string][]sample)
+	for _, metric := range containerCounters {
+		rates[metric] = seriesRates(data, counters, now, metric)
+	}
+	return buildContainers(samples(data, containerGauges...), rates)
+}
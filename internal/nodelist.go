@@ -0,0 +1,338 @@
+package promtop
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// nodeRow is a line of the node list in the selection modal
+type nodeRow struct {
+	index  int    // Index into nodeRefs
+	prefix string // Tree branch drawn before the name
+}
+
+// modalSize returns the width and height of the node selection modal (60% of the screen)
+func (m dashboardModel) modalSize() (int, int) {
+	return int(float64(m.width) * 0.6), int(float64(m.height) * 0.6)
+}
+
+// showSearch reports whether the search box is shown above the node list
+func (m dashboardModel) showSearch() bool {
+	return m.searching || m.search.Value() != ""
+}
+
+// nodeListHeight returns how many node rows fit in the modal
+// Account for: title (1) + position indicator (1) + search box (1 if shown)
+func (m dashboardModel) nodeListHeight() int {
+	_, modalHeight := m.modalSize()
+	height := modalHeight - 2
+	if m.showSearch() {
+		height--
+	}
+	return max(height, 1)
+}
+
+// visibleNodes returns the indexes of the nodes that pass the search filter
+// along with match details for highlighting
+// Source headers and groups stay visible while any of their nodes match
+// Nodes inside collapsed sources and groups are hidden unless a search is active
+func (m dashboardModel) visibleNodes() ([]int, map[int]nodeMatch) {
+	filter := parseNodeFilter(m.search.Value())
+	matches := make(map[int]nodeMatch)
+	shown := make([]bool, len(m.nodeRefs))
+
+	// ancestors holds the header and groups above the current node, by depth
+	var ancestors []int
+	for i, ref := range m.nodeRefs {
+		ancestors = append(ancestors[:min(ref.Depth, len(ancestors))], i)
+		above := ancestors[:len(ancestors)-1]
+
+		match, ok := filter.match(ref)
+		if ok && !filter.empty() {
+			matches[i] = match
+		}
+		if !ref.IsNode() {
+			// Decided once its nodes have been checked, unless there is nothing to filter
+			shown[i] = filter.empty() && !m.collapsedAbove(above)
+			continue
+		}
+
+		// A group matching the search brings in all of its nodes that pass the label filters
+		if !ok && filter.pattern != "" && filter.matchLabels(ref) {
+			ok = slices.ContainsFunc(above, func(a int) bool {
+				_, matched := matches[a]
+				return matched && m.nodeRefs[a].Type == "group"
+			})
+		}
+		if !ok || filter.empty() && m.collapsedAbove(above) {
+			continue
+		}
+		for _, a := range ancestors {
+			shown[a] = true
+		}
+	}
+
+	visible := make([]int, 0, len(m.nodeRefs))
+	for i := range shown {
+		if shown[i] {
+			visible = append(visible, i)
+		}
+	}
+	return visible, matches
+}
+
+// collapsedAbove reports whether any of the given headers or groups is collapsed
+func (m dashboardModel) collapsedAbove(ancestors []int) bool {
+	for _, a := range ancestors {
+		if m.collapsed[m.nodeRefs[a].GroupKey] {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeRows lays the visible nodes out as rows of a tree
+// Prometheus nodes hang off their source header and groups, node_exporter nodes stand alone
+func (m dashboardModel) nodeRows() ([]nodeRow, map[int]nodeMatch) {
+	visible, matches := m.visibleNodes()
+	rows := make([]nodeRow, len(visible))
+
+	// Work backwards to find which rows have a sibling further down
+	hasNext := make([]bool, len(visible))
+	var seen []bool
+	for v := len(visible) - 1; v >= 0; v-- {
+		depth := m.nodeRefs[visible[v]].Depth
+		for len(seen) <= depth {
+			seen = append(seen, false)
+		}
+		hasNext[v] = seen[depth]
+		seen[depth] = true
+		clear(seen[depth+1:])
+	}
+
+	// Then draw the branches, continuing the lines of ancestors that have more siblings
+	var open []bool
+	for v, i := range visible {
+		depth := m.nodeRefs[i].Depth
+		open = append(open[:min(depth, len(open))], hasNext[v])
+		rows[v].index = i
+		if depth == 0 {
+			continue
+		}
+
+		var prefix strings.Builder
+		for level := 1; level < depth; level++ {
+			if open[level] {
+				prefix.WriteString("│   ")
+			} else {
+				prefix.WriteString("    ")
+			}
+		}
+		if hasNext[v] {
+			prefix.WriteString("├── ")
+		} else {
+			prefix.WriteString("└── ")
+		}
+		rows[v].prefix = prefix.String()
+	}
+	return rows, matches
+}
+
+// selectedRow returns the row of the selected node, or -1 if it is hidden
+func selectedRow(rows []nodeRow, selected int) int {
+	return slices.IndexFunc(rows, func(row nodeRow) bool {
+		return row.index == selected
+	})
+}
+
+// nodePosition returns the position of the selection among the visible nodes and how many there are
+// Source and group headers aren't counted, a selected header gives the position of the first node after it
+func (m dashboardModel) nodePosition(rows []nodeRow) (int, int) {
+	selected := max(selectedRow(rows, m.selectedNode), 0)
+	position, total := 0, 0
+	for i, row := range rows {
+		if !m.nodeRefs[row.index].IsNode() {
+			continue
+		}
+		total++
+		if i >= selected && position == 0 {
+			position = total
+		}
+	}
+	if position == 0 {
+		position = total
+	}
+	return position, total
+}
+
+// moveSelection moves the modal cursor by delta visible nodes, stopping at either end
+func (m dashboardModel) moveSelection(delta int) dashboardModel {
+	rows, _ := m.nodeRows()
+	if len(rows) == 0 {
+		return m
+	}
+	pos := max(selectedRow(rows, m.selectedNode), 0)
+	pos = min(max(pos+delta, 0), len(rows)-1)
+	m.selectedNode = rows[pos].index
+	return m
+}
+
+// selectBestMatch moves the modal cursor to the node that best matches the search
+func (m dashboardModel) selectBestMatch() dashboardModel {
+	rows, matches := m.nodeRows()
+	best := -1
+	for _, row := range rows {
+		match, ok := matches[row.index]
+		if !ok || !m.nodeRefs[row.index].IsNode() {
+			continue
+		}
+		if best < 0 || match.score > matches[best].score {
+			best = row.index
+		}
+	}
+	// Nodes brought in by a matching group have no score of their own, so take the first
+	if best < 0 && len(rows) > 0 {
+		best = rows[0].index
+		if r := slices.IndexFunc(rows, func(row nodeRow) bool { return m.nodeRefs[row.index].IsNode() }); r >= 0 {
+			best = rows[r].index
+		}
+	}
+	if best >= 0 {
+		m.selectedNode = best
+	}
+	return m
+}
+
+// keepSelectionVisible moves the modal cursor to the best match if its node has been filtered out
+func (m dashboardModel) keepSelectionVisible() dashboardModel {
+	rows, _ := m.nodeRows()
+	if selectedRow(rows, m.selectedNode) < 0 {
+		return m.selectBestMatch()
+	}
+	return m
+}
+
+// scrollToSelection adjusts the node list offset so the cursor stays on screen
+func (m dashboardModel) scrollToSelection() dashboardModel {
+	rows, _ := m.nodeRows()
+	height := m.nodeListHeight()
+
+	if pos := selectedRow(rows, m.selectedNode); pos >= 0 {
+		if pos < m.nodeOffset {
+			m.nodeOffset = pos
+		} else if pos >= m.nodeOffset+height {
+			m.nodeOffset = pos - height + 1
+		}
+	}
+	m.nodeOffset = min(m.nodeOffset, max(len(rows)-height, 0))
+	m.nodeOffset = max(m.nodeOffset, 0)
+	return m
+}
+
+// renderNodeList renders the rows of the node list that fit in the modal
+// Nodes hidden by the search filter are skipped and matched characters highlighted
+// A scrollbar and position indicator show where the window is in a long list
+func (m dashboardModel) renderNodeList(width, height int) string {
+	selectedStyle := theme.Selected.
+		Foreground(theme.Focus).
+		Bold(true)
+
+	sourceHeaderStyle := lipgloss.NewStyle().
+		Foreground(theme.Header).
+		Bold(true)
+
+	normalStyle := lipgloss.NewStyle()
+
+	groupStyle := lipgloss.NewStyle().
+		Foreground(theme.Title)
+
+	matchStyle := lipgloss.NewStyle().
+		Foreground(theme.Focus).
+		Underline(true)
+
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	rows, matches := m.nodeRows()
+	if len(rows) == 0 {
+		return mutedStyle.Render("No matching nodes")
+	}
+
+	// Only render the rows inside the window
+	start := min(m.nodeOffset, len(rows))
+	end := min(start+height, len(rows))
+	scrollbar := renderScrollbar(start, end-start, len(rows), height)
+	lineStyle := lipgloss.NewStyle().MaxWidth(width - 2)
+
+	lines := make([]string, 0, height+1)
+	for r := start; r < end; r++ {
+		row := rows[r]
+		ref := m.nodeRefs[row.index]
+
+		style := sourceHeaderStyle
+		switch ref.Type {
+		case "prometheus_node":
+			style = normalStyle
+		case "group":
+			style = groupStyle
+		}
+
+		var label string
+		if row.index == m.selectedNode {
+			label = selectedStyle.Render(ref.DisplayName)
+		} else {
+			label = highlightMatches(ref.DisplayName, matches[row.index].positions, style.Render, matchStyle.Render)
+		}
+
+		// Headers and groups show how many nodes they hold, and whether they are collapsed
+		if !ref.IsNode() {
+			if m.collapsed[ref.GroupKey] {
+				label = mutedStyle.Render("▸ ") + label
+			}
+			label += mutedStyle.Render(fmt.Sprintf(" (%d)", ref.NodeCount))
+		}
+		if row.index == m.selectedNode {
+			label = selectedStyle.Render("▶ ") + label
+		}
+
+		line := lineStyle.Render(row.prefix + label)
+		if scrollbar != nil {
+			line = lipgloss.PlaceHorizontal(width-1, lipgloss.Left, line) + mutedStyle.Render(scrollbar[r-start])
+		}
+		lines = append(lines, line)
+	}
+
+	// Pad so the indicator sits at the bottom of the modal
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+
+	position, total := m.nodePosition(rows)
+	indicator := fmt.Sprintf("%d/%d", position, total)
+	lines = append(lines, mutedStyle.Width(width).Align(lipgloss.Right).Render(indicator))
+
+	return strings.Join(lines, "\n")
+}
+
+// renderScrollbar returns one scrollbar character per visible line
+// Returns nil when everything fits and no scrollbar is needed
+func renderScrollbar(offset, visible, total, height int) []string {
+	if total <= height {
+		return nil
+	}
+
+	thumbSize := max(height*height/total, 1)
+	thumbStart := offset * (height - thumbSize) / max(total-visible, 1)
+
+	bar := make([]string, height)
+	for i := range bar {
+		if i >= thumbStart && i < thumbStart+thumbSize {
+			bar[i] = "█"
+		} else {
+			bar[i] = "│"
+		}
+	}
+	return bar
+}
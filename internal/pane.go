@@ -33,7 +33,7 @@ type Pane struct {
 	focused     bool
 }
 
-// NewPane creates a new pane with default styling
+// NewPane creates a new pane styled with the active theme
 func NewPane(title string, width, height int) Pane {
 	return Pane{
 		title:  title,
@@ -41,9 +41,9 @@ func NewPane(title string, width, height int) Pane {
 		height: height,
 		borderStyle: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240")),
+			BorderForeground(theme.Border),
 		titleStyle: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("33")).
+			Foreground(theme.Title).
 			Bold(true),
 		focused: false,
 	}
@@ -84,9 +84,9 @@ func (p Pane) SetTitleStyle(style lipgloss.Style) Pane {
 func (p Pane) SetFocused(focused bool) Pane {
 	p.focused = focused
 	if focused {
-		p.borderStyle = p.borderStyle.BorderForeground(lipgloss.Color("170"))
+		p.borderStyle = p.borderStyle.BorderForeground(theme.Focus)
 	} else {
-		p.borderStyle = p.borderStyle.BorderForeground(lipgloss.Color("240"))
+		p.borderStyle = p.borderStyle.BorderForeground(theme.Border)
 	}
 	return p
 }
@@ -0,0 +1,62 @@
+package promtop
+
+import "fmt"
+
+// pressureMetrics are the PSI counters behind the pressure chart, keyed by the name of their value
+// "some" is time at least one task was stalled, "full" time all non-idle tasks were stalled at once
+// Kernels before 5.13 have no full figure for CPU
+var pressureMetrics = map[string]string{
+	"cpu_some":    "node_pressure_cpu_waiting_seconds_total",
+	"cpu_full":    "node_pressure_cpu_stalled_seconds_total",
+	"memory_some": "node_pressure_memory_waiting_seconds_total",
+	"memory_full": "node_pressure_memory_stalled_seconds_total",
+	"io_some":     "node_pressure_io_waiting_seconds_total",
+	"io_full":     "node_pressure_io_stalled_seconds_total",
+}
+
+// Stall percentages that need attention
+const (
+	pressureWarningPercent  = 10.0
+	pressureCriticalPercent = 40.0
+)
+
+// renderPressure renders the pressure chart: stall percentages for each resource with their trend
+func renderPressure(chart Chart, width, height int) string {
+	if chart.PressureData == nil {
+		return "Waiting for data..."
+	}
+	// The history is left empty when the node has no PSI metrics
+	if len(chart.PressureData) == 0 {
+		return "Pressure stall information is not available on this node (needs Linux 4.20+ with PSI enabled)"
+	}
+
+	// Leave room for the other columns and the table borders
+	trendWidth := max((width-30)/2, 0)
+
+	cell := func(name string) (string, string) {
+		history := chart.PressureData[name]
+		if len(history) == 0 {
+			return "-", ""
+		}
+		latest := history[len(history)-1]
+		st := statusFor(latest, pressureWarningPercent, pressureCriticalPercent)
+		return st.render(fmt.Sprintf("%.1f%%", latest)), st.render(sparkline(history, trendWidth, pressureWarningPercent))
+	}
+
+	rows := [][]string{}
+	for _, resource := range []struct{ name, label string }{
+		{"cpu", "CPU"},
+		{"memory", "Memory"},
+		{"io", "IO"},
+	} {
+		some, someTrend := cell(resource.name + "_some")
+		full, fullTrend := cell(resource.name + "_full")
+		rows = append(rows, []string{resource.label, some, someTrend, full, fullTrend})
+	}
+
+	return NewWrapTable().
+		MaxHeight(height).
+		Headers("Resource", "Some", "Trend", "Full", "Trend").
+		Rows(rows...).
+		Render()
+}
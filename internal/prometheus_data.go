@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"net"
 	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/api"
@@ -13,8 +17,10 @@ import (
 )
 
 type PrometheusData struct {
-	client api.Client
-	url    *url.URL
+	client          api.Client
+	url             *url.URL
+	scrapeIntervals map[string]time.Duration // Detected scrape interval per target selector
+	scrapeRetries   map[string]time.Time     // When to try again for targets whose interval couldn't be detected
 }
 
 func NewPrometheusData(prometheusURL *url.URL) (*PrometheusData, error) {
@@ -26,8 +32,10 @@ func NewPrometheusData(prometheusURL *url.URL) (*PrometheusData, error) {
 	}
 
 	return &PrometheusData{
-		client: client,
-		url:    prometheusURL,
+		client:          client,
+		url:             prometheusURL,
+		scrapeIntervals: make(map[string]time.Duration),
+		scrapeRetries:   make(map[string]time.Time),
 	}, nil
 }
 
@@ -79,15 +87,266 @@ func (p *PrometheusData) GetNodes() []string {
 	return nodes
 }
 
+// GetNodeLabels returns the target labels of each node from the up metric
+func (p *PrometheusData) GetNodeLabels() map[string]map[string]string {
+	v1api := v1.NewAPI(p.client)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, warnings, err := v1api.Query(ctx, "up{job=\"node_exporter\"}", time.Now())
+	if err != nil {
+		log.Fatalf("Error querying Prometheus: %v", err)
+	}
+	if len(warnings) > 0 {
+		log.Fatalf("Warnings: %v\n", warnings)
+	}
+
+	labels := make(map[string]map[string]string, result.(model.Vector).Len())
+	for _, val := range result.(model.Vector) {
+		nodeLabels := make(map[string]string, len(val.Metric))
+		for name, value := range val.Metric {
+			if name != model.MetricNameLabel {
+				nodeLabels[string(name)] = string(value)
+			}
+		}
+		labels[string(val.Metric["instance"])] = nodeLabels
+	}
+
+	return labels
+}
+
+// GetNodeNames returns the nodename each node reports in node_uname_info
+func (p *PrometheusData) GetNodeNames() map[string]string {
+	v1api := v1.NewAPI(p.client)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, warnings, err := v1api.Query(ctx, "node_uname_info{job=\"node_exporter\"}", time.Now())
+	if err != nil {
+		log.Fatalf("Error querying Prometheus: %v", err)
+	}
+	if len(warnings) > 0 {
+		log.Fatalf("Warnings: %v\n", warnings)
+	}
+
+	names := make(map[string]string, result.(model.Vector).Len())
+	for _, val := range result.(model.Vector) {
+		if nodename := string(val.Metric["nodename"]); nodename != "" {
+			names[string(val.Metric["instance"])] = nodename
+		}
+	}
+
+	return names
+}
+
+// scrapeIntervalRetry is how long to wait before trying to detect a scrape interval again
+const scrapeIntervalRetry = time.Minute
+
+// scrapeInterval returns how often Prometheus scrapes the target picked by a selector, such as nodeSelector(node)
+// It is detected from the spacing of the target's up samples and cached
+// Returns 0 if there are not yet enough samples to tell, and doesn't ask again for scrapeIntervalRetry
+func (p *PrometheusData) scrapeInterval(selector string) time.Duration {
+	if interval, ok := p.scrapeIntervals[selector]; ok {
+		return interval
+	}
+	if time.Now().Before(p.scrapeRetries[selector]) {
+		return 0
+	}
+
+	v1api := v1.NewAPI(p.client)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// up is written on every scrape so its samples are spaced by the scrape interval
+	query := fmt.Sprintf("up{%s}[10m]", selector)
+	result, _, err := v1api.Query(ctx, query, time.Now())
+	if err != nil {
+		log.Printf("Failed to detect scrape interval for %s: %v", selector, err)
+		p.scrapeRetries[selector] = time.Now().Add(scrapeIntervalRetry)
+		return 0
+	}
+	matrix, ok := result.(model.Matrix)
+	if !ok || len(matrix) == 0 || len(matrix[0].Values) < 2 {
+		p.scrapeRetries[selector] = time.Now().Add(scrapeIntervalRetry)
+		return 0
+	}
+
+	// use the smallest gap so a missed scrape doesn't inflate the interval
+	var interval time.Duration
+	values := matrix[0].Values
+	for i := 1; i < len(values); i++ {
+		gap := values[i].Timestamp.Sub(values[i-1].Timestamp)
+		if gap > 0 && (interval == 0 || gap < interval) {
+			interval = gap
+		}
+	}
+
+	log.Printf("Detected scrape interval for %s: %s", selector, interval)
+	p.scrapeIntervals[selector] = interval
+	delete(p.scrapeRetries, selector)
+	return interval
+}
+
+// rateInterval returns the range to use in rate queries for a node formatted for Prometheus (e.g. "60s")
+func (p *PrometheusData) rateInterval(node string) string {
+	return p.targetRateInterval(nodeSelector(node))
+}
+
+// targetRateInterval returns the range to use in rate queries for the target picked by a selector
+// Like Grafana's $__rate_interval it is at least four scrape intervals
+// so the window always holds enough samples even if a scrape is missed
+func (p *PrometheusData) targetRateInterval(selector string) string {
+	window := time.Duration(CPU_RATE_INTERVAL) * time.Second
+	if safe := 4 * p.scrapeInterval(selector); safe > window {
+		window = safe
+	}
+	return fmt.Sprintf("%ds", int(math.Ceil(window.Seconds())))
+}
+
+// hostRateInterval returns the range to use in rate queries for a metric from another target on the node's host
+// It follows the scrape interval of the target publishing the metric, falling back to the node's
+func (p *PrometheusData) hostRateInterval(node, metric string) string {
+	targets := p.samples(fmt.Sprintf("group by (instance, job) (%s{%s})", metric, hostSelector(node)))[""]
+	if len(targets) == 0 {
+		return p.rateInterval(node)
+	}
+	target := targets[0].labels
+	return p.targetRateInterval(fmt.Sprintf("instance=\"%s\",job=\"%s\"", target["instance"], target["job"]))
+}
+
+// nodeSelector returns the label matchers that select a node's series
+func nodeSelector(node string) string {
+	return fmt.Sprintf("instance=\"%s\",job=\"node_exporter\"", node)
+}
+
+// query runs an instant query for the optional metrics behind the extra charts
+// Failures are logged and give an empty result so one missing collector doesn't stop promtop
+func (p *PrometheusData) query(query string) model.Vector {
+	v1api := v1.NewAPI(p.client)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, warnings, err := v1api.Query(ctx, query, time.Now())
+	if err != nil {
+		log.Printf("Error querying Prometheus: %v", err)
+		return nil
+	}
+	if len(warnings) > 0 {
+		log.Printf("Prometheus warnings: %v", warnings)
+	}
+	vector, _ := result.(model.Vector)
+	return vector
+}
+
+// gauges returns the current value of each of the named metrics for a node, keyed by metric name
+// Metrics the node doesn't expose are left out
+func (p *PrometheusData) gauges(node string, names ...string) map[string]float64 {
+	values := make(map[string]float64)
+	query := fmt.Sprintf("{__name__=~\"%s\",%s}", strings.Join(names, "|"), nodeSelector(node))
+	for _, val := range p.query(query) {
+		values[string(val.Metric[model.MetricNameLabel])] = float64(val.Value)
+	}
+	return values
+}
+
+// counterRate returns the per-second rate of a counter for a node, summed over all of its series
+func (p *PrometheusData) counterRate(node, metric string) (float64, bool) {
+	return p.scalar(fmt.Sprintf(
+		"sum(%s(%s{%s}[%s]))",
+		config.RateFunction,
+		metric,
+		nodeSelector(node),
+		p.rateInterval(node),
+	))
+}
+
+// samples runs a query and returns every series it gives, grouped by metric name
+// Series without a name, such as the result of a function, are grouped under ""
+func (p *PrometheusData) samples(query string) map[string][]sample {
+	samples := make(map[string][]sample)
+	for _, val := range p.query(query) {
+		labels := make(map[string]string, len(val.Metric))
+		for name, value := range val.Metric {
+			labels[string(name)] = string(value)
+		}
+		name := labels[model.MetricNameLabel]
+		samples[name] = append(samples[name], sample{labels: labels, value: float64(val.Value)})
+	}
+	return samples
+}
+
+// nodeSamples returns every series of the named metrics for a node, grouped by metric name
+func (p *PrometheusData) nodeSamples(node string, names ...string) map[string][]sample {
+	return p.samples(fmt.Sprintf("{__name__=~\"%s\",%s}", strings.Join(names, "|"), nodeSelector(node)))
+}
+
+// hostSamples returns every series of the named metrics from any target on the node's host, whatever its job and port
+// This finds exporters that run alongside node_exporter, such as the IPMI and smartctl exporters
+func (p *PrometheusData) hostSamples(node string, names ...string) map[string][]sample {
+	return p.samples(fmt.Sprintf("{__name__=~\"%s\",%s}", strings.Join(names, "|"), hostSelector(node)))
+}
+
+// hostRates returns the per-second rate of each series of a counter from any target on the node's host
+// interval is the rate range, see hostRateInterval
+func (p *PrometheusData) hostRates(node, metric, interval string) []sample {
+	return p.samples(fmt.Sprintf(
+		"%s(%s{%s}[%s])",
+		config.RateFunction,
+		metric,
+		hostSelector(node),
+		interval,
+	))[""]
+}
+
+// hostSelector returns the label matcher that selects the series of every target on a node's host
+func hostSelector(node string) string {
+	host := node
+	if h, _, err := net.SplitHostPort(node); err == nil {
+		host = h
+	}
+	return fmt.Sprintf("instance=~\"%s(:[0-9]+)?\"", regexp.QuoteMeta(host))
+}
+
+// seriesRates returns the per-second rate of each series of a counter for a node
+func (p *PrometheusData) seriesRates(node, metric string) []sample {
+	return p.samples(fmt.Sprintf(
+		"%s(%s{%s}[%s])",
+		config.RateFunction,
+		metric,
+		nodeSelector(node),
+		p.rateInterval(node),
+	))[""]
+}
+
+// seriesDerivs returns how fast each series of a gauge for a node changes per second
+func (p *PrometheusData) seriesDerivs(node, metric string) []sample {
+	return p.samples(fmt.Sprintf(
+		"deriv(%s{%s}[%s])",
+		metric,
+		nodeSelector(node),
+		p.rateInterval(node),
+	))[""]
+}
+
+// scalar returns the value of a query that gives a single series
+func (p *PrometheusData) scalar(query string) (float64, bool) {
+	vector := p.query(query)
+	if len(vector) == 0 {
+		return 0, false
+	}
+	return float64(vector[0].Value), true
+}
+
 func (p *PrometheusData) GetCpu(node string) map[string]float64 {
+	interval := p.rateInterval(node)
+
 	v1api := v1.NewAPI(p.client)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	query := fmt.Sprintf(
-		"100 - (avg by (instance,cpu) (rate(node_cpu_seconds_total{instance=\"%s\",job=\"node_exporter\",mode=\"idle\"}[%s])) * 100)",
+		"100 - (avg by (instance,cpu) (%s(node_cpu_seconds_total{instance=\"%s\",job=\"node_exporter\",mode=\"idle\"}[%s])) * 100)",
+		config.RateFunction,
 		node,
-		CPURateIntervalString(),
+		interval,
 	)
 
 	result, warnings, err := v1api.Query(ctx, query, time.Now())
@@ -198,9 +457,244 @@ func (p *PrometheusData) GetMemory(node string) map[string]float64 {
 		}
 	}
 
+	// Detailed breakdown (Linux only)
+	names := make([]string, 0, len(memoryDetailMetrics))
+	for _, metric := range memoryDetailMetrics {
+		names = append(names, metric)
+	}
+	details := p.gauges(node, names...)
+	for name, metric := range memoryDetailMetrics {
+		if value, ok := details[metric]; ok {
+			memory[name] = value
+		}
+	}
+
 	return memory
 }
 
+// GetInfo returns the fields describing a node, empty if Prometheus can't be queried
+func (p *PrometheusData) GetInfo(node string) map[string]string {
+	// Fetch all the info metrics in one query
+	series := make(map[string][]map[string]string)
+	values := make(map[string]float64)
+	for name, samples := range p.nodeSamples(node, infoMetrics...) {
+		for _, s := range samples {
+			series[name] = append(series[name], s.labels)
+		}
+		values[name] = samples[0].value
+	}
+
+	return buildNodeInfo(series, values)
+}
+
+func (p *PrometheusData) GetLoad(node string) map[string]float64 {
+	load := make(map[string]float64)
+	for name, value := range p.gauges(node, loadMetrics...) {
+		load[strings.TrimPrefix(name, "node_")] = value
+	}
+
+	if cores, ok := p.scalar(fmt.Sprintf("count(node_cpu_seconds_total{%s,mode=\"idle\"})", nodeSelector(node))); ok {
+		load["cores"] = cores
+	}
+
+	// Time tasks spent waiting for a CPU, summed over every CPU
+	if waiting, ok := p.counterRate(node, "node_schedstat_waiting_seconds_total"); ok {
+		load["sched_waiting"] = waiting
+	}
+
+	return load
+}
+
+// GetPressure returns the percentage of time tasks were stalled on each resource
+// Returns nil if the node doesn't expose PSI
+func (p *PrometheusData) GetPressure(node string) map[string]float64 {
+	var pressure map[string]float64
+	for name, metric := range pressureMetrics {
+		if rate, ok := p.counterRate(node, metric); ok {
+			if pressure == nil {
+				pressure = make(map[string]float64)
+			}
+			pressure[name] = rate * 100
+		}
+	}
+	return pressure
+}
+
+func (p *PrometheusData) GetSwap(node string) map[string]float64 {
+	values := p.gauges(node, "node_memory_SwapTotal_bytes", "node_memory_SwapFree_bytes", "node_vmstat_oom_kill")
+	for name, metric := range pagingMetrics {
+		if rate, ok := p.counterRate(node, metric); ok {
+			values[name] = rate
+		}
+	}
+	return buildSwap(values)
+}
+
+// GetSensors returns the node's temperature and fan sensors
+// IPMI readings come from an IPMI exporter target on the same host
+func (p *PrometheusData) GetSensors(node string) []Sensor {
+	samples := p.nodeSamples(node, sensorMetrics...)
+	for name, series := range p.hostSamples(node, ipmiSensorMetrics...) {
+		samples[name] = append(samples[name], series...)
+	}
+	return buildSensors(samples)
+}
+
+func (p *PrometheusData) GetFrequency(node string) CPUFrequency {
+	return buildCPUFrequency(
+		p.nodeSamples(node, frequencyMetrics...),
+		p.seriesRates(node, coreThrottlesMetric),
+		p.seriesRates(node, packageThrottlesMetric),
+	)
+}
+
+func (p *PrometheusData) GetKernel(node string) map[string]float64 {
+	return buildKernel(p.gauges(node, kernelMetrics...))
+}
+
 func (p *PrometheusData) GetType() string {
 	return "prometheus"
 }
+
+func (p *PrometheusData) GetNetStack(node string) NetStack {
+	values := make(map[string]float64)
+	if established, ok := p.gauges(node, establishedMetric)[establishedMetric]; ok {
+		values["established"] = established
+	}
+	for name, metric := range netstatRateMetrics {
+		if rate, ok := p.counterRate(node, metric); ok {
+			values[name] = rate
+		}
+	}
+	return NetStack{
+		Values: values,
+		Softnet: buildSoftnet(
+			p.seriesRates(node, softnetProcessedMetric),
+			p.seriesRates(node, softnetDroppedMetric),
+			p.seriesRates(node, softnetSqueezedMetric),
+		),
+	}
+}
+
+func (p *PrometheusData) GetNetwork(node string) []NetworkInterface {
+	return buildNetwork(
+		p.nodeSamples(node, networkMetrics...),
+		p.seriesRates(node, networkReceiveMetric),
+		p.seriesRates(node, networkTransmitMetric),
+	)
+}
+
+// GetDiskHealth returns the node's RAID arrays, drives and disk error rates
+// SMART readings come from a smartctl_exporter target on the same host
+func (p *PrometheusData) GetDiskHealth(node string) DiskHealth {
+	samples := p.nodeSamples(node, mdMetrics...)
+	for name, series := range p.hostSamples(node, smartMetrics...) {
+		samples[name] = append(samples[name], series...)
+	}
+
+	// rate drops the metric name, so each error counter the node has is rated on its own
+	errorRates := make(map[string][]sample)
+	for name := range p.nodeSamples(node, diskErrorPattern) {
+		errorRates[name] = p.seriesRates(node, name)
+	}
+	return buildDiskHealth(samples, p.seriesDerivs(node, mdSyncedMetric), errorRates)
+}
+
+// GetSystemd returns the node's systemd units, and how many units have failed on each node of the source
+func (p *PrometheusData) GetSystemd(node string) SystemdUnits {
+	units := buildSystemd(p.nodeSamples(node, systemdMetrics...), config.SystemdUnits)
+
+	failed := p.samples("node_systemd_unit_state{job=\"node_exporter\",state=\"failed\"}")
+	units.FailedNodes = failedUnitsByNode(failed["node_systemd_unit_state"], config.SystemdUnits)
+	return units
+}
+
+// GetTimeSync returns the clock state of every node of the source
+// Skew is measured against the time Prometheus scraped node_time_seconds, so it doesn't depend on the local clock
+func (p *PrometheusData) GetTimeSync() []NodeClock {
+	values := make(map[string]map[string]float64)
+	query := fmt.Sprintf("{__name__=~\"%s\",job=\"node_exporter\"}", strings.Join(timexMetrics, "|"))
+	for name, series := range p.samples(query) {
+		for _, s := range series {
+			node := s.labels["instance"]
+			if values[node] == nil {
+				values[node] = make(map[string]float64)
+			}
+			values[node][name] = s.value
+		}
+	}
+	skews := make(map[string]float64)
+	query = fmt.Sprintf("%s{job=\"node_exporter\"} - timestamp(%s{job=\"node_exporter\"})", nodeTimeMetric, nodeTimeMetric)
+	for _, s := range p.samples(query)[""] {
+		skews[s.labels["instance"]] = s.value
+	}
+
+	// Targets that are down are listed as unreachable
+	var clocks []NodeClock
+	for _, s := range p.samples("up{job=\"node_exporter\"}")["up"] {
+		node := s.labels["instance"]
+		if s.value != 1 {
+			clocks = append(clocks, NodeClock{Node: node})
+			continue
+		}
+		skew, hasSkew := skews[node]
+		clocks = append(clocks, buildClock(node, values[node], skew, hasSkew))
+	}
+	return clocks
+}
+
+// GetEnergy returns the node's power draw in watts per RAPL domain and package
+// with the power draw of every node of the source under "source_total"
+func (p *PrometheusData) GetEnergy(node string) map[string]float64 {
+	rates := make(map[string][]sample)
+	for domain, metric := range raplMetrics {
+		rates[domain] = p.seriesRates(node, metric)
+	}
+	energy := buildEnergy(rates)
+
+	total, ok := p.scalar(fmt.Sprintf(
+		"sum(%s({__name__=~\"%s|%s\",job=\"node_exporter\"}[%s]))",
+		config.RateFunction,
+		raplMetrics["package"],
+		raplMetrics["dram"],
+		p.rateInterval(node),
+	))
+	if ok {
+		energy["source_total"] = total
+	}
+	// Prometheus has the earlier readings, so no rates means no RAPL counters
+	if len(energy) == 0 {
+		return nil
+	}
+	return energy
+}
+
+func (p *PrometheusData) GetNFS(node string) NFSStats {
+	rates := make(map[string][]sample)
+	for _, metric := range nfsMetrics {
+		rates[metric] = p.seriesRates(node, metric)
+	}
+	return buildNFS(rates)
+}
+
+func (p *PrometheusData) GetTopology(node string) CPUTopology {
+	return buildCPUTopology(
+		p.nodeSamples(node, append([]string{"node_cpu_info"}, numaMetrics...)...),
+		p.seriesRates(node, numaMissMetric),
+	)
+}
+
+func (p *PrometheusData) GetInterrupts(node string) Interrupts {
+	return buildInterrupts(p.seriesRates(node, interruptsMetric), p.seriesRates(node, softirqsMetric))
+}
+
+// GetContainers returns the containers on the node from a cAdvisor target on the same host
+func (p *PrometheusData) GetContainers(node string) Containers {
+	// cAdvisor is often scraped on a different schedule to node_exporter
+	interval := p.hostRateInterval(node, containerCheckMetric)
+	rates := make(map[string][]sample)
+	for _, metric := range containerCounters {
+		rates[metric] = p.hostRates(node, metric, interval)
+	}
+	return buildContainers(p.hostSamples(node, containerGauges...), rates)
+}
@@ -0,0 +1,20 @@
+package promtop
+
+// sample is the current value of one series of a metric along with its labels
+// It lets both data sources hand the same readings to the code that builds a chart
+type sample struct {
+	labels map[string]string
+	value  float64
+}
+
+// sampleKey joins the values of the named labels to identify a series, e.g. chip and sensor
+func sampleKey(s sample, names ...string) string {
+	key := ""
+	for i, name := range names {
+		if i > 0 {
+			key += "/"
+		}
+		key += s.labels[name]
+	}
+	return key
+}
@@ -0,0 +1,21 @@
+package promtop
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+// parseSamples reads metrics recorded from an exporter, in the Prometheus text format, into samples grouped by metric name
+func parseSamples(t *testing.T, text string) map[string][]sample {
+	t.Helper()
+	var parser expfmt.TextParser
+	data, err := parser.TextToMetricFamilies(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("parsing recorded metrics: %v", err)
+	}
+	return samples(data, slices.Collect(maps.Keys(data))...)
+}
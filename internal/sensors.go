@@ -0,0 +1,216 @@
+package promtop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sensor is a temperature or fan reading from node_exporter or the IPMI exporter
+type Sensor struct {
+	Kind     string  // "temperature" or "fan"
+	Source   string  // "hwmon", "thermal" or "ipmi"
+	Name     string  // Readable name, e.g. "coretemp Package id 0"
+	Value    float64 // Degrees Celsius or RPM
+	Max      float64 // Temperature that needs attention, 0 if not published
+	Critical float64 // Temperature where the hardware protects itself, 0 if not published
+	Min      float64 // Lowest healthy fan speed, 0 if not published
+	State    status  // State reported by the exporter itself (IPMI)
+}
+
+// sensorMetrics are the node_exporter metrics behind the sensors chart
+var sensorMetrics = []string{
+	"node_hwmon_temp_celsius",
+	"node_hwmon_temp_max_celsius",
+	"node_hwmon_temp_crit_celsius",
+	"node_hwmon_fan_rpm",
+	"node_hwmon_fan_min_rpm",
+	"node_hwmon_sensor_label",
+	"node_hwmon_chip_names",
+	"node_thermal_zone_temp",
+}
+
+// ipmiSensorMetrics are the IPMI exporter metrics behind the sensors chart
+var ipmiSensorMetrics = []string{
+	"ipmi_temperature_celsius",
+	"ipmi_temperature_state",
+	"ipmi_fan_speed_rpm",
+	"ipmi_fan_speed_state",
+}
+
+// Without a published max, temperatures this close to critical need attention
+const sensorCriticalMargin = 10.0
+
+// buildSensors turns the series of the sensor metrics into sensor readings sorted by kind, source and name
+func buildSensors(samples map[string][]sample) []Sensor {
+	var sensors []Sensor
+
+	// hwmon sensors are named by chip and sensor, with readable names in separate info metrics
+	chipNames := make(map[string]string)
+	for _, s := range samples["node_hwmon_chip_names"] {
+		chipNames[s.labels["chip"]] = s.labels["chip_name"]
+	}
+	labels := make(map[string]string)
+	for _, s := range samples["node_hwmon_sensor_label"] {
+		labels[sampleKey(s, "chip", "sensor")] = s.labels["label"]
+	}
+	thresholds := func(metric string) map[string]float64 {
+		values := make(map[string]float64)
+		for _, s := range samples[metric] {
+			values[sampleKey(s, "chip", "sensor")] = s.value
+		}
+		return values
+	}
+	hwmonName := func(s sample) string {
+		chip := chipNames[s.labels["chip"]]
+		if chip == "" {
+			chip = s.labels["chip"]
+		}
+		label := labels[sampleKey(s, "chip", "sensor")]
+		if label == "" {
+			label = s.labels["sensor"]
+		}
+		return chip + " " + label
+	}
+
+	maxTemps := thresholds("node_hwmon_temp_max_celsius")
+	critTemps := thresholds("node_hwmon_temp_crit_celsius")
+	for _, s := range samples["node_hwmon_temp_celsius"] {
+		key := sampleKey(s, "chip", "sensor")
+		sensors = append(sensors, Sensor{
+			Kind:     "temperature",
+			Source:   "hwmon",
+			Name:     hwmonName(s),
+			Value:    s.value,
+			Max:      maxTemps[key],
+			Critical: critTemps[key],
+		})
+	}
+
+	minFans := thresholds("node_hwmon_fan_min_rpm")
+	for _, s := range samples["node_hwmon_fan_rpm"] {
+		sensors = append(sensors, Sensor{
+			Kind:   "fan",
+			Source: "hwmon",
+			Name:   hwmonName(s),
+			Value:  s.value,
+			Min:    minFans[sampleKey(s, "chip", "sensor")],
+		})
+	}
+
+	for _, s := range samples["node_thermal_zone_temp"] {
+		sensors = append(sensors, Sensor{
+			Kind:   "temperature",
+			Source: "thermal",
+			Name:   s.labels["type"] + " " + s.labels["zone"],
+			Value:  s.value,
+		})
+	}
+
+	// The IPMI exporter reports 0 for nominal, 1 for warning and 2 for critical
+	states := func(metric string) map[string]status {
+		values := make(map[string]status)
+		for _, s := range samples[metric] {
+			values[s.labels["id"]] = status(min(max(int(s.value), 0), int(statusCritical)))
+		}
+		return values
+	}
+	tempStates := states("ipmi_temperature_state")
+	for _, s := range samples["ipmi_temperature_celsius"] {
+		sensors = append(sensors, Sensor{
+			Kind:   "temperature",
+			Source: "ipmi",
+			Name:   s.labels["name"],
+			Value:  s.value,
+			State:  tempStates[s.labels["id"]],
+		})
+	}
+	fanStates := states("ipmi_fan_speed_state")
+	for _, s := range samples["ipmi_fan_speed_rpm"] {
+		sensors = append(sensors, Sensor{
+			Kind:   "fan",
+			Source: "ipmi",
+			Name:   s.labels["name"],
+			Value:  s.value,
+			State:  fanStates[s.labels["id"]],
+		})
+	}
+
+	sort.Slice(sensors, func(i, j int) bool {
+		a, b := sensors[i], sensors[j]
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind // temperatures first
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Name < b.Name
+	})
+	return sensors
+}
+
+// status compares the reading against the thresholds the exporter published
+func (s Sensor) status() status {
+	st := s.State
+	switch s.Kind {
+	case "temperature":
+		warning := s.Max
+		if warning == 0 && s.Critical > 0 {
+			warning = s.Critical - sensorCriticalMargin
+		}
+		if s.Critical > 0 && s.Value >= s.Critical {
+			st = max(st, statusCritical)
+		} else if warning > 0 && s.Value >= warning {
+			st = max(st, statusWarning)
+		}
+	case "fan":
+		if s.Min > 0 && s.Value < s.Min {
+			st = max(st, statusCritical)
+		}
+	}
+	return st
+}
+
+// renderSensors renders the sensors chart: each temperature and fan with its thresholds
+func renderSensors(chart Chart, height int) string {
+	if chart.SensorData == nil {
+		return "Waiting for data..."
+	}
+	if len(chart.SensorData) == 0 {
+		return "No temperature or fan sensors available on this node"
+	}
+
+	threshold := func(value float64, unit string) string {
+		if value == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%.0f%s", value, unit)
+	}
+
+	rows := [][]string{}
+	for _, s := range chart.SensorData {
+		st := s.status()
+		name := strings.TrimSpace(s.Name)
+		if s.Kind == "fan" {
+			rows = append(rows, []string{
+				name + " (fan)",
+				st.render(fmt.Sprintf("%.0f RPM", s.Value)),
+				threshold(s.Min, " RPM min"),
+				"",
+			})
+			continue
+		}
+		rows = append(rows, []string{
+			name,
+			st.render(fmt.Sprintf("%.1f°C", s.Value)),
+			threshold(s.Max, "°C"),
+			threshold(s.Critical, "°C"),
+		})
+	}
+
+	return NewWrapTable().
+		MaxHeight(height).
+		Headers("Sensor", "Value", "Max", "Critical").
+		Rows(rows...).
+		Render()
+}
@@ -0,0 +1,105 @@
+package promtop
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recordedSensors is the sensor output of node_exporter and the IPMI exporter on a single host
+const recordedSensors = `# HELP node_hwmon_chip_names Annotation metric for human-readable chip names
+# TYPE node_hwmon_chip_names gauge
+node_hwmon_chip_names{chip="platform_coretemp_0",chip_name="coretemp"} 1
+# HELP node_hwmon_sensor_label Label for given chip and sensor
+# TYPE node_hwmon_sensor_label gauge
+node_hwmon_sensor_label{chip="platform_coretemp_0",label="Core 0",sensor="temp2"} 1
+node_hwmon_sensor_label{chip="platform_coretemp_0",label="Package id 0",sensor="temp1"} 1
+# HELP node_hwmon_temp_celsius Hardware monitor for temperature (input)
+# TYPE node_hwmon_temp_celsius gauge
+node_hwmon_temp_celsius{chip="platform_coretemp_0",sensor="temp1"} 52
+node_hwmon_temp_celsius{chip="platform_coretemp_0",sensor="temp2"} 49
+# HELP node_hwmon_temp_crit_celsius Hardware monitor for temperature (crit)
+# TYPE node_hwmon_temp_crit_celsius gauge
+node_hwmon_temp_crit_celsius{chip="platform_coretemp_0",sensor="temp1"} 100
+# HELP node_hwmon_temp_max_celsius Hardware monitor for temperature (max)
+# TYPE node_hwmon_temp_max_celsius gauge
+node_hwmon_temp_max_celsius{chip="platform_coretemp_0",sensor="temp1"} 80
+# HELP node_hwmon_fan_rpm Hardware monitor for fan revolutions per minute (input)
+# TYPE node_hwmon_fan_rpm gauge
+node_hwmon_fan_rpm{chip="platform_nct6775_656",sensor="fan2"} 1103
+# HELP node_hwmon_fan_min_rpm Hardware monitor for fan revolutions per minute (min)
+# TYPE node_hwmon_fan_min_rpm gauge
+node_hwmon_fan_min_rpm{chip="platform_nct6775_656",sensor="fan2"} 300
+# HELP node_thermal_zone_temp Zone temperature in Celsius
+# TYPE node_thermal_zone_temp gauge
+node_thermal_zone_temp{type="x86_pkg_temp",zone="0"} 53
+# HELP ipmi_temperature_celsius Temperature reading in degree Celsius.
+# TYPE ipmi_temperature_celsius gauge
+ipmi_temperature_celsius{id="4",name="CPU Temp"} 45
+# HELP ipmi_temperature_state Reported state of a temperature sensor (0=nominal, 1=warning, 2=critical).
+# TYPE ipmi_temperature_state gauge
+ipmi_temperature_state{id="4",name="CPU Temp"} 0
+# HELP ipmi_fan_speed_rpm Fan speed in rotations per minute.
+# TYPE ipmi_fan_speed_rpm gauge
+ipmi_fan_speed_rpm{id="12",name="FAN1"} 4200
+# HELP ipmi_fan_speed_state Reported state of a fan speed sensor (0=nominal, 1=warning, 2=critical).
+# TYPE ipmi_fan_speed_state gauge
+ipmi_fan_speed_state{id="12",name="FAN1"} 1
+`
+
+func TestBuildSensors(t *testing.T) {
+	tests := []struct {
+		name     string
+		recorded string
+		want     []Sensor
+	}{
+		{
+			name:     "hwmon, thermal and ipmi",
+			recorded: recordedSensors,
+			want: []Sensor{
+				{Kind: "temperature", Source: "hwmon", Name: "coretemp Core 0", Value: 49},
+				{Kind: "temperature", Source: "hwmon", Name: "coretemp Package id 0", Value: 52, Max: 80, Critical: 100},
+				{Kind: "temperature", Source: "ipmi", Name: "CPU Temp", Value: 45, State: statusOK},
+				{Kind: "temperature", Source: "thermal", Name: "x86_pkg_temp 0", Value: 53},
+				{Kind: "fan", Source: "hwmon", Name: "platform_nct6775_656 fan2", Value: 1103, Min: 300},
+				{Kind: "fan", Source: "ipmi", Name: "FAN1", Value: 4200, State: statusWarning},
+			},
+		},
+		{
+			name:     "no sensors",
+			recorded: "node_load1 0.5\n",
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildSensors(parseSamples(t, tt.recorded))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildSensors() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSensorStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		sensor Sensor
+		want   status
+	}{
+		{name: "below max", sensor: Sensor{Kind: "temperature", Value: 70, Max: 80, Critical: 100}, want: statusOK},
+		{name: "above max", sensor: Sensor{Kind: "temperature", Value: 85, Max: 80, Critical: 100}, want: statusWarning},
+		{name: "at critical", sensor: Sensor{Kind: "temperature", Value: 100, Max: 80, Critical: 100}, want: statusCritical},
+		{name: "near critical without max", sensor: Sensor{Kind: "temperature", Value: 91, Critical: 100}, want: statusWarning},
+		{name: "no thresholds", sensor: Sensor{Kind: "temperature", Value: 120}, want: statusOK},
+		{name: "fan below min", sensor: Sensor{Kind: "fan", Value: 200, Min: 300}, want: statusCritical},
+		{name: "stopped fan without min", sensor: Sensor{Kind: "fan", Value: 0}, want: statusOK},
+		{name: "exporter state", sensor: Sensor{Kind: "fan", Value: 4200, State: statusWarning}, want: statusWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sensor.status(); got != tt.want {
+				t.Errorf("status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,7 +12,7 @@ type DetectedSource struct {
 }
 
 // TryConnectWithFallbacks tries multiple URL variants to connect to data sources
-// Returns all successful connections (can be both Prometheus and node_exporter)
+// Returns all successful connections (can be any of Prometheus, node_exporter and cAdvisor)
 func TryConnectWithFallbacks(baseURL *url.URL) []DetectedSource {
 	var detected []DetectedSource
 
@@ -59,6 +59,26 @@ func TryConnectWithFallbacks(baseURL *url.URL) []DetectedSource {
 		break
 	}
 
+	// Try cAdvisor with all variants
+	var cadvisorData Data
+	var cadvisorURL *url.URL
+	for _, variant := range variants {
+		log.Printf("Trying cAdvisor backend: %s", variant)
+		cd, err := NewCAdvisorData([]*url.URL{variant})
+		if err != nil {
+			log.Printf("Failed to create cAdvisor client: %v", err)
+			continue
+		}
+		if err := cd.Check(); err != nil {
+			log.Printf("cAdvisor check failed: %v", err)
+			continue
+		}
+		log.Printf("✓ Found cAdvisor backend at %s", variant)
+		cadvisorData = cd
+		cadvisorURL = variant
+		break
+	}
+
 	// Add successful connections
 	if promData != nil {
 		detected = append(detected, DetectedSource{
@@ -74,6 +94,13 @@ func TryConnectWithFallbacks(baseURL *url.URL) []DetectedSource {
 		})
 	}
 
+	if cadvisorData != nil {
+		detected = append(detected, DetectedSource{
+			Data: cadvisorData,
+			Name: cadvisorURL.Host,
+		})
+	}
+
 	return detected
 }
 
@@ -94,10 +121,10 @@ func generateURLVariants(base *url.URL) []*url.URL {
 	var ports []string
 	if port != "" {
 		// Use specified port first, then try common ports
-		ports = []string{port, "9090", "9100", "443", "80"}
+		ports = []string{port, "9090", "9100", "8080", "443", "80"}
 	} else {
-		// Try common ports: 9090 (Prometheus), 9100 (node_exporter), 443 (HTTPS), 80 (HTTP)
-		ports = []string{"9090", "9100", "443", "80"}
+		// Try common ports: 9090 (Prometheus), 9100 (node_exporter), 8080 (cAdvisor), 443 (HTTPS), 80 (HTTP)
+		ports = []string{"9090", "9100", "8080", "443", "80"}
 	}
 
 	// Remove duplicates
@@ -0,0 +1,74 @@
+package promtop
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// status is how worrying a value is, used to colour values in charts
+type status int
+
+const (
+	statusOK status = iota
+	statusWarning
+	statusCritical
+)
+
+// statusFor compares a value against warning and critical thresholds
+func statusFor(value, warning, critical float64) status {
+	switch {
+	case value >= critical:
+		return statusCritical
+	case value >= warning:
+		return statusWarning
+	default:
+		return statusOK
+	}
+}
+
+// style returns the style used to render values with this status
+// Critical values are also bold so they stand out without color
+func (s status) style() lipgloss.Style {
+	switch s {
+	case statusCritical:
+		return lipgloss.NewStyle().Foreground(theme.Critical).Bold(true)
+	case statusWarning:
+		return lipgloss.NewStyle().Foreground(theme.Warning)
+	default:
+		return lipgloss.NewStyle()
+	}
+}
+
+// render renders text in the style of the status
+func (s status) render(text string) string {
+	if s == statusOK {
+		return text
+	}
+	return s.style().Render(text)
+}
+
+// sparkBlocks are the bar heights used by sparklines, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws the last width values as a row of bars scaled to ceiling
+// The ceiling grows to the largest value so spikes are never cut off
+func sparkline(values []float64, width int, ceiling float64) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	values = values[max(len(values)-width, 0):]
+	for _, v := range values {
+		ceiling = max(ceiling, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if ceiling > 0 {
+			level = int(v / ceiling * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[min(max(level, 0), len(sparkBlocks)-1)])
+	}
+	return b.String()
+}
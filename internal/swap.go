@@ -0,0 +1,122 @@
+package promtop
+
+import "fmt"
+
+// pagingMetrics are the vmstat counters behind the swap chart, keyed by the name of their rate
+var pagingMetrics = map[string]string{
+	"swap_in":      "node_vmstat_pswpin",     // Pages swapped in
+	"swap_out":     "node_vmstat_pswpout",    // Pages swapped out
+	"page_in":      "node_vmstat_pgpgin",     // KiB paged in from disk
+	"page_out":     "node_vmstat_pgpgout",    // KiB paged out to disk
+	"major_faults": "node_vmstat_pgmajfault", // Faults that had to read from disk
+}
+
+// Swap use that needs attention, as a percentage of swap space
+const (
+	swapWarningPercent  = 50.0
+	swapCriticalPercent = 80.0
+)
+
+// buildSwap turns the swap gauges, keyed by metric name, and the paging rates into the values shown by the swap chart
+func buildSwap(values map[string]float64) map[string]float64 {
+	swap := make(map[string]float64)
+	for name := range pagingMetrics {
+		if rate, ok := values[name]; ok {
+			swap[name] = rate
+		}
+	}
+	if kills, ok := values["node_vmstat_oom_kill"]; ok {
+		swap["oom_kills"] = kills
+	}
+
+	total, hasTotal := values["node_memory_SwapTotal_bytes"]
+	free, hasFree := values["node_memory_SwapFree_bytes"]
+	if hasTotal && hasFree {
+		swap["swap_total"] = total
+		swap["swap_free"] = free
+		swap["swap_used"] = total - free
+		if total > 0 {
+			swap["swap_used_percent"] = (total - free) / total * 100
+		}
+	}
+	return swap
+}
+
+// renderSwap renders the swap chart: swap space, paging rates with their trend and OOM kills
+func renderSwap(chart Chart, width, height int) string {
+	if len(chart.SwapData) == 0 {
+		return "Waiting for data..."
+	}
+
+	latest := func(name string) (float64, bool) {
+		history := chart.SwapData[name]
+		if len(history) == 0 {
+			return 0, false
+		}
+		return history[len(history)-1], true
+	}
+
+	// Leave room for the other columns and the table borders
+	trendWidth := max(width-40, 0)
+
+	rows := [][]string{}
+	if total, ok := latest("swap_total"); ok {
+		if total == 0 {
+			rows = append(rows, []string{"Swap", "none configured", ""})
+		} else {
+			used, _ := latest("swap_used")
+			usedPct, _ := latest("swap_used_percent")
+			st := statusFor(usedPct, swapWarningPercent, swapCriticalPercent)
+			rows = append(rows,
+				[]string{"Swap total", fmt.Sprintf("%.2f GB", total/(1024*1024*1024)), ""},
+				[]string{
+					"Swap used",
+					st.render(fmt.Sprintf("%.2f GB (%.1f%%)", used/(1024*1024*1024), usedPct)),
+					st.render(sparkline(chart.SwapData["swap_used_percent"], trendWidth, 100)),
+				},
+			)
+		}
+	}
+
+	// Any swapping shows memory is short, so highlight it
+	for _, rate := range []struct{ name, label, unit string }{
+		{"swap_in", "Swap in", "pages/s"},
+		{"swap_out", "Swap out", "pages/s"},
+		{"page_in", "Page in", "KiB/s"},
+		{"page_out", "Page out", "KiB/s"},
+		{"major_faults", "Major faults", "/s"},
+	} {
+		value, ok := latest(rate.name)
+		if !ok {
+			continue
+		}
+		st := statusOK
+		if value > 0 && (rate.name == "swap_in" || rate.name == "swap_out") {
+			st = statusWarning
+		}
+		rows = append(rows, []string{
+			rate.label,
+			st.render(fmt.Sprintf("%.1f %s", value, rate.unit)),
+			sparkline(chart.SwapData[rate.name], trendWidth, 1),
+		})
+	}
+
+	// Kills since the start of the history are new, the counter itself counts since boot
+	if kills, ok := latest("oom_kills"); ok {
+		value := fmt.Sprintf("%.0f since boot", kills)
+		if recent := kills - chart.SwapData["oom_kills"][0]; recent > 0 {
+			value = statusCritical.render(fmt.Sprintf("%.0f since boot, %.0f new", kills, recent))
+		}
+		rows = append(rows, []string{"OOM kills", value, ""})
+	}
+
+	if len(rows) == 0 {
+		return "No swap metrics available"
+	}
+
+	return NewWrapTable().
+		MaxHeight(height).
+		Headers("Metric", "Value", "Trend").
+		Rows(rows...).
+		Render()
+}
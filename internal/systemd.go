@@ -0,0 +1,195 @@
+package promtop
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// SystemdUnits is the state of a node's systemd units
+type SystemdUnits struct {
+	Units       []SystemdUnit      // Failed units, then units changing state, then with a filter any other match
+	Counts      map[string]float64 // Units per state
+	FailedNodes map[string]float64 // Failed units per node across the source, nil if the source can't tell
+}
+
+// SystemdUnit is a systemd unit and its active state
+type SystemdUnit struct {
+	Name  string
+	State string // "active", "activating", "deactivating", "failed", "inactive" or "reloading"
+}
+
+// systemdMetrics are the gauges behind the systemd chart
+var systemdMetrics = []string{
+	"node_systemd_unit_state",
+	"node_systemd_units",
+}
+
+// systemdStates are the unit states in the order they are listed and counted, the ones that need attention first
+var systemdStates = []string{"failed", "activating", "deactivating", "reloading", "active", "inactive"}
+
+// matchUnit reports whether a unit name matches one of the configured patterns, any unit matches without patterns
+func matchUnit(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validateUnitPatterns checks the systemd unit patterns are valid globs
+func validateUnitPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid systemd unit pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// buildSystemd turns the systemd series into the units listed by the systemd chart
+// Only units matching the patterns are listed and, when there are patterns, counted
+func buildSystemd(samples map[string][]sample, patterns []string) SystemdUnits {
+	units := SystemdUnits{Counts: make(map[string]float64)}
+
+	// One series per unit and state, the current state is 1
+	for _, s := range samples["node_systemd_unit_state"] {
+		name := s.labels["name"]
+		if s.value != 1 || !matchUnit(name, patterns) {
+			continue
+		}
+		state := s.labels["state"]
+		if len(patterns) > 0 {
+			units.Counts[state]++
+		}
+		if len(patterns) > 0 || (state != "active" && state != "inactive") {
+			units.Units = append(units.Units, SystemdUnit{Name: name, State: state})
+		}
+	}
+
+	// node_systemd_units counts every unit, not only the ones the collector publishes a state for
+	if len(patterns) == 0 {
+		for _, s := range samples["node_systemd_units"] {
+			units.Counts[s.labels["state"]] = s.value
+		}
+	}
+
+	order := make(map[string]int, len(systemdStates))
+	for i, state := range systemdStates {
+		order[state] = i
+	}
+	sort.Slice(units.Units, func(i, j int) bool {
+		a, b := units.Units[i], units.Units[j]
+		if a.State != b.State {
+			return order[a.State] < order[b.State]
+		}
+		return a.Name < b.Name
+	})
+	return units
+}
+
+// failedUnitsByNode counts the failed units matching the patterns on each node, keyed by instance
+// samples are the node_systemd_unit_state series for the failed state
+func failedUnitsByNode(samples []sample, patterns []string) map[string]float64 {
+	failed := make(map[string]float64)
+	for _, s := range samples {
+		if s.value == 1 && matchUnit(s.labels["name"], patterns) {
+			failed[s.labels["instance"]]++
+		}
+	}
+	return failed
+}
+
+// status is critical for a failed unit and a warning for one changing state
+func (u SystemdUnit) status() status {
+	switch u.State {
+	case "failed":
+		return statusCritical
+	case "activating", "deactivating", "reloading":
+		return statusWarning
+	}
+	return statusOK
+}
+
+// renderSystemd renders the systemd chart: counts per state, the units that need attention
+// and for Prometheus sources the nodes with failed units, grouped like the node list
+func renderSystemd(chart Chart, height int) string {
+	if chart.SystemdData == nil {
+		return "Waiting for data..."
+	}
+	units := *chart.SystemdData
+	if len(units.Counts) == 0 && len(units.Units) == 0 && len(units.FailedNodes) == 0 {
+		return "Systemd metrics are not available on this node"
+	}
+
+	var b strings.Builder
+
+	counts := []string{}
+	for _, state := range systemdStates {
+		count := units.Counts[state]
+		text := fmt.Sprintf("%s %.0f", state, count)
+		if count > 0 {
+			text = SystemdUnit{State: state}.status().render(text)
+		}
+		counts = append(counts, text)
+	}
+	b.WriteString(strings.Join(counts, ", "))
+	if len(config.SystemdUnits) > 0 {
+		b.WriteString(" (matching " + strings.Join(config.SystemdUnits, " ") + ")")
+	}
+	b.WriteString("\n")
+	used := 1
+
+	if len(units.Units) > 0 {
+		rows := [][]string{}
+		for _, u := range units.Units {
+			st := u.status()
+			rows = append(rows, []string{st.render(u.Name), st.render(u.State)})
+		}
+		t := NewWrapTable().
+			MaxHeight(height-used).
+			Headers("Unit", "State").
+			Rows(rows...).
+			Render()
+		b.WriteString(t)
+		b.WriteString("\n")
+		used += strings.Count(t, "\n") + 1
+	} else if units.Counts["failed"] == 0 {
+		b.WriteString(statusOK.render("No failed units"))
+		b.WriteString("\n")
+		used++
+	}
+
+	if len(units.FailedNodes) > 0 {
+		nodes := make([]string, 0, len(units.FailedNodes))
+		for node := range units.FailedNodes {
+			nodes = append(nodes, node)
+		}
+		name := func(node string) string {
+			return nodeDisplayName(node, chart.NodeNames[node])
+		}
+		sort.Slice(nodes, func(i, j int) bool {
+			if units.FailedNodes[nodes[i]] != units.FailedNodes[nodes[j]] {
+				return units.FailedNodes[nodes[i]] > units.FailedNodes[nodes[j]]
+			}
+			return name(nodes[i]) < name(nodes[j])
+		})
+		rows := groupedRows(nodes, chart.NodeLabels, 2, func(node string) []string {
+			return []string{
+				name(node),
+				statusCritical.render(fmt.Sprintf("%.0f", units.FailedNodes[node])),
+			}
+		})
+		b.WriteString(NewWrapTable().
+			MaxHeight(max(height-used, 5)).
+			Headers("Nodes with failed units", "Failed").
+			Rows(rows...).
+			Render())
+	}
+	return b.String()
+}
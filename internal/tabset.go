@@ -77,6 +77,14 @@ func (ts *TabSet) GetChartPointer(index int) *Chart {
 	return nil
 }
 
+// ToggleExpanded shows or hides the detailed view of the current tab's chart
+func (ts *TabSet) ToggleExpanded() *TabSet {
+	if chart := ts.GetChartPointer(ts.selectedTab); chart != nil {
+		chart.Expanded = !chart.Expanded
+	}
+	return ts
+}
+
 // GetSelectedTab returns the currently selected tab index
 func (ts *TabSet) GetSelectedTab() int {
 	return ts.selectedTab
@@ -112,7 +120,7 @@ func (ts *TabSet) Render() string {
 
 	// Show hostname of currently selected chart above tabs
 	hostnameStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("214")).
+		Foreground(theme.Header).
 		Bold(true)
 	b.WriteString(hostnameStyle.Render(selectedChart.NodeRef.DisplayName))
 	b.WriteString("\n")
@@ -137,19 +145,19 @@ func (ts *TabSet) Render() string {
 
 // renderTabs renders the tab navigation bar
 func (ts *TabSet) renderTabs() string {
-	activeTabStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("170")).
-		Background(lipgloss.Color("235")).
+	activeTabStyle := theme.Selected.
+		Foreground(theme.Focus).
+		Background(theme.Surface).
 		Bold(true).
 		Padding(0, 1).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("170"))
+		BorderForeground(theme.Focus)
 
 	inactiveTabStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(theme.Muted).
 		Padding(0, 1).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("236"))
+		BorderForeground(theme.Subtle)
 
 	var renderedTabs []string
 	for i, chart := range ts.charts {
@@ -158,12 +166,48 @@ func (ts *TabSet) renderTabs() string {
 		switch chart.ChartType {
 		case "cpu":
 			label = "CPU"
+			if chart.Expanded {
+				label += " +"
+			}
 		case "memory":
 			label = "Memory"
+			if chart.Expanded {
+				label += " +"
+			}
 		case "disk":
 			label = "Disk"
 		case "network":
 			label = "Network"
+		case "info":
+			label = "Info"
+		case "load":
+			label = "Load"
+		case "pressure":
+			label = "Pressure"
+		case "swap":
+			label = "Swap"
+		case "sensors":
+			label = "Sensors"
+		case "frequency":
+			label = "Frequency"
+		case "kernel":
+			label = "Kernel"
+		case "netstat":
+			label = "Net stack"
+		case "diskhealth":
+			label = "Disk health"
+		case "systemd":
+			label = "Systemd"
+		case "timesync":
+			label = "Time sync"
+		case "energy":
+			label = "Energy"
+		case "nfs":
+			label = "NFS"
+		case "interrupts":
+			label = "Interrupts"
+		case "containers":
+			label = "Containers"
 		default:
 			label = chart.ChartType
 		}
@@ -184,7 +228,9 @@ func (ts *TabSet) renderChartContent(chart Chart, width, height int) string {
 
 	switch chart.ChartType {
 	case "cpu":
-		if len(chart.CpuData) > 0 {
+		if chart.Expanded {
+			content.WriteString(renderCPUTopology(chart, width, height))
+		} else if len(chart.CpuData) > 0 {
 			// Create table for CPU data
 			rows := [][]string{}
 
@@ -216,7 +262,6 @@ func (ts *TabSet) renderChartContent(chart Chart, width, height int) string {
 
 			// Use WrapTable to handle wrapping when content exceeds height
 			t := NewWrapTable().
-				BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("240"))).
 				MaxHeight(height).
 				Headers("Core", "Usage").
 				Rows(rows...)
@@ -310,9 +355,13 @@ func (ts *TabSet) renderChartContent(chart Chart, width, height int) string {
 				})
 			}
 
+			// Where the used memory went
+			if chart.Expanded {
+				rows = append(rows, memoryDetailRows(chart.MemoryData)...)
+			}
+
 			// Use WrapTable to handle wrapping when content exceeds height
 			t := NewWrapTable().
-				BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("240"))).
 				MaxHeight(height).
 				Headers("Metric", "Value").
 				Rows(rows...)
@@ -324,7 +373,37 @@ func (ts *TabSet) renderChartContent(chart Chart, width, height int) string {
 	case "disk":
 		content.WriteString("Disk metrics coming soon...")
 	case "network":
-		content.WriteString("Network metrics coming soon...")
+		content.WriteString(renderNetwork(chart, width, height))
+	case "info":
+		content.WriteString(renderInfo(chart, height))
+	case "load":
+		content.WriteString(renderLoad(chart, width, height))
+	case "pressure":
+		content.WriteString(renderPressure(chart, width, height))
+	case "swap":
+		content.WriteString(renderSwap(chart, width, height))
+	case "sensors":
+		content.WriteString(renderSensors(chart, height))
+	case "frequency":
+		content.WriteString(renderFrequency(chart, width, height))
+	case "kernel":
+		content.WriteString(renderKernel(chart, width, height))
+	case "netstat":
+		content.WriteString(renderNetStack(chart, height))
+	case "diskhealth":
+		content.WriteString(renderDiskHealth(chart, height))
+	case "systemd":
+		content.WriteString(renderSystemd(chart, height))
+	case "timesync":
+		content.WriteString(renderTimeSync(chart, height))
+	case "energy":
+		content.WriteString(renderEnergy(chart, width, height))
+	case "nfs":
+		content.WriteString(renderNFS(chart, height))
+	case "interrupts":
+		content.WriteString(renderInterrupts(chart, width, height))
+	case "containers":
+		content.WriteString(renderContainers(chart, height))
 	default:
 		content.WriteString("Unsupported chart type")
 	}
@@ -0,0 +1,163 @@
+package promtop
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds every color used by the UI
+type Theme struct {
+	Title    lipgloss.TerminalColor // Pane and modal titles
+	Border   lipgloss.TerminalColor // Unfocused pane and table borders
+	Focus    lipgloss.TerminalColor // Focused pane border, selected node and active tab
+	Header   lipgloss.TerminalColor // Hostnames and source headers
+	Muted    lipgloss.TerminalColor // Help text and inactive tabs
+	Surface  lipgloss.TerminalColor // Help bar and active tab background
+	Subtle   lipgloss.TerminalColor // Inactive tab borders and modal backdrop
+	Warning  lipgloss.TerminalColor // Values that need attention
+	Critical lipgloss.TerminalColor // Values that need action
+	Selected lipgloss.Style         // Extra attributes for the selected node, so it stands out without color
+}
+
+// ThemeConfig is a user-defined theme from the config file
+// Colors are ANSI numbers ("214") or hex values ("#ffaf00"), missing colors come from the built-in base theme
+type ThemeConfig struct {
+	Base     string `mapstructure:"base"`
+	Title    string `mapstructure:"title"`
+	Border   string `mapstructure:"border"`
+	Focus    string `mapstructure:"focus"`
+	Header   string `mapstructure:"header"`
+	Muted    string `mapstructure:"muted"`
+	Surface  string `mapstructure:"surface"`
+	Subtle   string `mapstructure:"subtle"`
+	Warning  string `mapstructure:"warning"`
+	Critical string `mapstructure:"critical"`
+}
+
+// builtinThemes are the themes available without any configuration
+var builtinThemes = map[string]Theme{
+	"dark": {
+		Title:    lipgloss.Color("33"),
+		Border:   lipgloss.Color("240"),
+		Focus:    lipgloss.Color("170"),
+		Header:   lipgloss.Color("214"),
+		Muted:    lipgloss.Color("240"),
+		Surface:  lipgloss.Color("235"),
+		Subtle:   lipgloss.Color("236"),
+		Warning:  lipgloss.Color("220"),
+		Critical: lipgloss.Color("196"),
+		Selected: lipgloss.NewStyle().Bold(true),
+	},
+	"light": {
+		Title:    lipgloss.Color("25"),
+		Border:   lipgloss.Color("248"),
+		Focus:    lipgloss.Color("127"),
+		Header:   lipgloss.Color("130"),
+		Muted:    lipgloss.Color("243"),
+		Surface:  lipgloss.Color("254"),
+		Subtle:   lipgloss.Color("252"),
+		Warning:  lipgloss.Color("166"),
+		Critical: lipgloss.Color("160"),
+		Selected: lipgloss.NewStyle().Bold(true),
+	},
+	"solarized": {
+		Title:    lipgloss.Color("#268bd2"),
+		Border:   lipgloss.Color("#586e75"),
+		Focus:    lipgloss.Color("#d33682"),
+		Header:   lipgloss.Color("#b58900"),
+		Muted:    lipgloss.Color("#657b83"),
+		Surface:  lipgloss.Color("#073642"),
+		Subtle:   lipgloss.Color("#073642"),
+		Warning:  lipgloss.Color("#cb4b16"),
+		Critical: lipgloss.Color("#dc322f"),
+		Selected: lipgloss.NewStyle().Bold(true),
+	},
+	// colorblind uses the Okabe-Ito palette, which stays distinguishable with all common forms of color blindness
+	"colorblind": {
+		Title:    lipgloss.Color("#56b4e9"),
+		Border:   lipgloss.Color("240"),
+		Focus:    lipgloss.Color("#e69f00"),
+		Header:   lipgloss.Color("#009e73"),
+		Muted:    lipgloss.Color("245"),
+		Surface:  lipgloss.Color("235"),
+		Subtle:   lipgloss.Color("237"),
+		Warning:  lipgloss.Color("#f0e442"),
+		Critical: lipgloss.Color("#d55e00"),
+		Selected: lipgloss.NewStyle().Bold(true).Underline(true),
+	},
+}
+
+// noColorTheme is used when NO_COLOR is set (https://no-color.org)
+// Selection is shown with text attributes instead
+var noColorTheme = Theme{
+	Title:    lipgloss.NoColor{},
+	Border:   lipgloss.NoColor{},
+	Focus:    lipgloss.NoColor{},
+	Header:   lipgloss.NoColor{},
+	Muted:    lipgloss.NoColor{},
+	Surface:  lipgloss.NoColor{},
+	Subtle:   lipgloss.NoColor{},
+	Warning:  lipgloss.NoColor{},
+	Critical: lipgloss.NoColor{},
+	Selected: lipgloss.NewStyle().Bold(true).Reverse(true),
+}
+
+// theme is the active theme, set once at startup
+var theme = builtinThemes["dark"]
+
+// ThemeNames returns the names of the built-in themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveTheme finds a theme by name, checking user-defined themes before the built-in ones
+// NO_COLOR overrides any choice
+func resolveTheme(name string, themes map[string]ThemeConfig) (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return noColorTheme, nil
+	}
+
+	if custom, ok := themes[name]; ok {
+		base := custom.Base
+		if base == "" {
+			base = "dark"
+		}
+		t, ok := builtinThemes[base]
+		if !ok {
+			return Theme{}, fmt.Errorf("theme %s: unknown base theme: %s", name, base)
+		}
+		custom.apply(&t)
+		return t, nil
+	}
+
+	if t, ok := builtinThemes[name]; ok {
+		return t, nil
+	}
+	return Theme{}, fmt.Errorf("unknown theme: %s", name)
+}
+
+// apply overrides the colors of a theme with the ones set in the config
+func (tc ThemeConfig) apply(t *Theme) {
+	set := func(dst *lipgloss.TerminalColor, value string) {
+		if value != "" {
+			*dst = lipgloss.Color(value)
+		}
+	}
+	set(&t.Title, tc.Title)
+	set(&t.Border, tc.Border)
+	set(&t.Focus, tc.Focus)
+	set(&t.Header, tc.Header)
+	set(&t.Muted, tc.Muted)
+	set(&t.Surface, tc.Surface)
+	set(&t.Subtle, tc.Subtle)
+	set(&t.Warning, tc.Warning)
+	set(&t.Critical, tc.Critical)
+}
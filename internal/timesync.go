@@ -0,0 +1,143 @@
+package promtop
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NodeClock is the clock state of a node
+type NodeClock struct {
+	Node      string
+	Reachable bool
+	HasTimex  bool    // The timex collector is enabled
+	Synced    bool    // The kernel considers the clock synchronised
+	Offset    float64 // Offset from the time source in seconds, as the kernel sees it
+	MaxError  float64 // Maximum error in seconds
+	HasSkew   bool
+	Skew      float64 // Node time minus the reference clock in seconds
+}
+
+// timexMetrics are the gauges behind the time sync chart
+var timexMetrics = []string{
+	"node_timex_offset_seconds",
+	"node_timex_sync_status",
+	"node_timex_maxerror_seconds",
+}
+
+// nodeTimeMetric is the node's clock, compared with the reference clock to find its skew
+const nodeTimeMetric = "node_time_seconds"
+
+// Clock offsets and skews that need attention, in seconds either way
+const (
+	clockWarningSeconds  = 0.05
+	clockCriticalSeconds = 0.5
+)
+
+// buildClock turns a node's timex gauges, keyed by metric name, and its skew into its clock state
+func buildClock(node string, values map[string]float64, skew float64, hasSkew bool) NodeClock {
+	c := NodeClock{Node: node, Reachable: true, Skew: skew, HasSkew: hasSkew}
+	if offset, ok := values["node_timex_offset_seconds"]; ok {
+		c.HasTimex = true
+		c.Offset = offset
+	}
+	if synced, ok := values["node_timex_sync_status"]; ok {
+		c.HasTimex = true
+		c.Synced = synced == 1
+	}
+	c.MaxError = values["node_timex_maxerror_seconds"]
+	return c
+}
+
+// sortClocks orders clocks by how much attention they need, then by the name shown for their node
+// names are the nodenames of the nodes, keyed by node
+func sortClocks(clocks []NodeClock, names map[string]string) {
+	sort.Slice(clocks, func(i, j int) bool {
+		a, b := clocks[i], clocks[j]
+		if a.status() != b.status() {
+			return a.status() > b.status()
+		}
+		return nodeDisplayName(a.Node, names[a.Node]) < nodeDisplayName(b.Node, names[b.Node])
+	})
+}
+
+// status is critical for an unsynchronised or unreachable clock, otherwise it follows the larger of offset and skew
+func (c NodeClock) status() status {
+	if !c.Reachable || (c.HasTimex && !c.Synced) {
+		return statusCritical
+	}
+	return statusFor(max(math.Abs(c.Offset), math.Abs(c.Skew)), clockWarningSeconds, clockCriticalSeconds)
+}
+
+// formatSeconds formats a short duration in seconds with a unit that keeps it readable
+func formatSeconds(seconds float64) string {
+	switch abs := math.Abs(seconds); {
+	case abs >= 1:
+		return fmt.Sprintf("%+.2f s", seconds)
+	case abs >= 1e-3:
+		return fmt.Sprintf("%+.2f ms", seconds*1e3)
+	default:
+		return fmt.Sprintf("%+.0f µs", seconds*1e6)
+	}
+}
+
+// renderTimeSync renders the time sync chart: the clock of every node of the source
+// grouped like the node list, problems first within each group
+func renderTimeSync(chart Chart, height int) string {
+	if chart.TimeSyncData == nil {
+		return "Waiting for data..."
+	}
+	if len(chart.TimeSyncData) == 0 {
+		return "No nodes available"
+	}
+
+	clocks := slices.Clone(chart.TimeSyncData)
+	sortClocks(clocks, chart.NodeNames)
+	nodes := make([]string, 0, len(clocks))
+	byNode := make(map[string]NodeClock, len(clocks))
+	for _, c := range clocks {
+		nodes = append(nodes, c.Node)
+		byNode[c.Node] = c
+	}
+
+	rows := groupedRows(nodes, chart.NodeLabels, 5, func(node string) []string {
+		c := byNode[node]
+		st := c.status()
+		name := nodeDisplayName(c.Node, chart.NodeNames[c.Node])
+		if c.Node == chart.NodeRef.NodeName {
+			name = "▶ " + name
+		}
+		if !c.Reachable {
+			return []string{name, st.render("unreachable"), "", "", ""}
+		}
+
+		synced, offset, maxError, skew := "", "", "", ""
+		if c.HasTimex {
+			synced = "yes"
+			if !c.Synced {
+				synced = "no"
+			}
+			offset = statusFor(math.Abs(c.Offset), clockWarningSeconds, clockCriticalSeconds).render(formatSeconds(c.Offset))
+			maxError = formatSeconds(c.MaxError)
+		}
+		if c.HasSkew {
+			skew = statusFor(math.Abs(c.Skew), clockWarningSeconds, clockCriticalSeconds).render(formatSeconds(c.Skew))
+		}
+		return []string{name, st.render(synced), offset, strings.TrimPrefix(maxError, "+"), skew}
+	})
+
+	return NewWrapTable().
+		MaxHeight(height).
+		Headers("Node", "Synced", "Offset", "Max error", "Skew").
+		Rows(rows...).
+		Render()
+}
+
+// clockSkew returns how far a node's clock was from the reference time, halfway through the request that read it
+func clockSkew(nodeTime float64, start, end time.Time) float64 {
+	reference := start.Add(end.Sub(start) / 2)
+	return nodeTime - float64(reference.UnixNano())/1e9
+}
@@ -0,0 +1,197 @@
+package promtop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CPUTopology places a node's logical CPUs on its sockets and cores, alongside its NUMA memory
+type CPUTopology struct {
+	CPUs []CPUPlacement // Sorted by package, core then CPU number
+	NUMA []NUMANode     // Sorted by node number
+}
+
+// CPUPlacement is the socket and physical core of a logical CPU, SMT siblings share a core
+type CPUPlacement struct {
+	CPU     string
+	Package string
+	Core    string
+}
+
+// NUMANode is the memory of a NUMA node
+type NUMANode struct {
+	Node   string
+	Total  float64 // Bytes
+	Free   float64 // Bytes
+	Misses float64 // Allocations per second that wanted this node but got another
+}
+
+// numaMetrics are the gauges behind the NUMA summary
+var numaMetrics = []string{
+	"node_memory_numa_MemTotal",
+	"node_memory_numa_MemFree",
+}
+
+// numaMissMetric counts allocations that fell back to another NUMA node
+const numaMissMetric = "node_memory_numa_numa_miss_total"
+
+// buildCPUTopology turns node_cpu_info and the NUMA series into the topology of a node
+func buildCPUTopology(samples map[string][]sample, misses []sample) CPUTopology {
+	var topology CPUTopology
+
+	for _, s := range samples["node_cpu_info"] {
+		topology.CPUs = append(topology.CPUs, CPUPlacement{
+			CPU:     s.labels["cpu"],
+			Package: s.labels["package"],
+			Core:    s.labels["core"],
+		})
+	}
+	sort.Slice(topology.CPUs, func(i, j int) bool {
+		a, b := topology.CPUs[i], topology.CPUs[j]
+		if a.Package != b.Package {
+			return numericLess(a.Package, b.Package)
+		}
+		if a.Core != b.Core {
+			return numericLess(a.Core, b.Core)
+		}
+		return numericLess(a.CPU, b.CPU)
+	})
+
+	nodes := make(map[string]*NUMANode)
+	node := func(s sample) *NUMANode {
+		name := s.labels["node"]
+		if nodes[name] == nil {
+			nodes[name] = &NUMANode{Node: name}
+		}
+		return nodes[name]
+	}
+	for _, s := range samples["node_memory_numa_MemTotal"] {
+		node(s).Total = s.value
+	}
+	for _, s := range samples["node_memory_numa_MemFree"] {
+		node(s).Free = s.value
+	}
+	for _, s := range misses {
+		node(s).Misses = s.value
+	}
+	for _, n := range nodes {
+		topology.NUMA = append(topology.NUMA, *n)
+	}
+	sort.Slice(topology.NUMA, func(i, j int) bool {
+		return numericLess(topology.NUMA[i].Node, topology.NUMA[j].Node)
+	})
+	return topology
+}
+
+// renderCPUTopology renders the detailed CPU chart: usage grouped by socket, physical core and SMT thread,
+// with the NUMA memory summary alongside
+func renderCPUTopology(chart Chart, width, height int) string {
+	if chart.TopologyData == nil || len(chart.CpuData) == 0 {
+		return "Waiting for data..."
+	}
+	topology := *chart.TopologyData
+	if len(topology.CPUs) == 0 {
+		return "CPU topology is not available on this node, node_cpu_info is missing"
+	}
+
+	latest := func(cpu string) (float64, bool) {
+		history := chart.CpuData[cpu]
+		if len(history) == 0 {
+			return 0, false
+		}
+		return history[len(history)-1], true
+	}
+
+	// One row per physical core, listing its threads side by side
+	rows := [][]string{}
+	socketTotals := make(map[string][]float64)
+	previous := ""
+	for i := 0; i < len(topology.CPUs); {
+		first := topology.CPUs[i]
+		var threads []string
+		total, count := 0.0, 0
+		for ; i < len(topology.CPUs) && topology.CPUs[i].Package == first.Package && topology.CPUs[i].Core == first.Core; i++ {
+			cpu := topology.CPUs[i].CPU
+			usage, ok := latest(cpu)
+			if !ok {
+				threads = append(threads, cpu+" -")
+				continue
+			}
+			threads = append(threads, fmt.Sprintf("%s %s %3.0f%%", cpu, sparkline([]float64{usage}, 1, 100), usage))
+			total += usage
+			count++
+			socketTotals[first.Package] = append(socketTotals[first.Package], usage)
+		}
+		// Show the socket only on its first core
+		socket := ""
+		if first.Package != previous || len(rows) == 0 {
+			socket = first.Package
+			previous = first.Package
+		}
+		average := ""
+		if count > 0 {
+			average = fmt.Sprintf("%.1f%%", total/float64(count))
+		}
+		rows = append(rows, []string{socket, first.Core, strings.Join(threads, "  "), average})
+	}
+
+	var summary []string
+	packages := make([]string, 0, len(socketTotals))
+	for pkg := range socketTotals {
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return numericLess(packages[i], packages[j])
+	})
+	for _, pkg := range packages {
+		total := 0.0
+		for _, usage := range socketTotals[pkg] {
+			total += usage
+		}
+		summary = append(summary, fmt.Sprintf("Socket %s %.1f%%", pkg, total/float64(len(socketTotals[pkg]))))
+	}
+
+	cpuTable := NewWrapTable().
+		MaxHeight(height-1).
+		Headers("Socket", "Core", "Threads", "Core avg").
+		Rows(rows...).
+		Render()
+
+	left := strings.Join(summary, ", ") + "\n" + cpuTable
+	if len(topology.NUMA) == 0 {
+		return left
+	}
+
+	numaRows := [][]string{}
+	for _, n := range topology.NUMA {
+		used := n.Total - n.Free
+		percent := 0.0
+		if n.Total > 0 {
+			percent = used / n.Total * 100
+		}
+		misses := fmt.Sprintf("%.0f/s", n.Misses)
+		if n.Misses > 0 {
+			misses = statusWarning.render(misses)
+		}
+		numaRows = append(numaRows, []string{
+			n.Node,
+			formatBytes(n.Total),
+			fmt.Sprintf("%s (%.1f%%)", formatBytes(used), percent),
+			misses,
+		})
+	}
+	numaTable := NewWrapTable().
+		MaxHeight(height-1).
+		Headers("NUMA", "Total", "Used", "Misses").
+		Rows(numaRows...).
+		Render()
+
+	// Stack the NUMA summary underneath when there isn't room beside the CPUs
+	if lipgloss.Width(left)+lipgloss.Width(numaTable)+2 > width {
+		return left + "\n" + numaTable
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, "  ", "\n"+numaTable)
+}
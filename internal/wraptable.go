@@ -20,7 +20,7 @@ type WrapTable struct {
 func NewWrapTable() *WrapTable {
 	return &WrapTable{
 		border:      lipgloss.NormalBorder(),
-		borderStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		borderStyle: lipgloss.NewStyle().Foreground(theme.Border),
 	}
 }
 
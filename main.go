@@ -9,6 +9,7 @@ import (
 
 	promtop "github.com/jondoveston/promtop/internal"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var version = "dev"
@@ -22,13 +23,13 @@ func main() {
 
 var rootCmd = &cobra.Command{
 	Use:   "promtop <url> [url...]",
-	Short: "Terminal-based dashboard for Prometheus/node_exporter metrics",
+	Short: "Terminal-based dashboard for Prometheus/node_exporter/cAdvisor metrics",
 	Long: `promtop displays real-time CPU, memory, disk, and network metrics
-from Prometheus or node_exporter in an interactive terminal interface.
+from Prometheus, node_exporter or cAdvisor in an interactive terminal interface.
 
-Each URL can point to either a Prometheus server or a node_exporter endpoint.
-promtop will automatically detect which backend to use for each URL by trying
-Prometheus first, then falling back to node_exporter if needed.
+Each URL can point to a Prometheus server, a node_exporter endpoint or a
+cAdvisor endpoint. promtop will automatically detect which backend to use for
+each URL by trying Prometheus first, then node_exporter, then cAdvisor.
 
 When multiple URLs are provided, nodes from all sources are displayed together
 with source prefixes (e.g., "prometheus.lan:9090:hostname").
@@ -36,6 +37,7 @@ with source prefixes (e.g., "prometheus.lan:9090:hostname").
 Examples:
   promtop http://prometheus.lan:9090
   promtop http://localhost:9100/metrics
+  promtop http://localhost:8080/metrics
   promtop http://prom1:9090 http://prom2:9090
   promtop http://prometheus.lan:9090 http://localhost:9100/metrics`,
 	Args: func(cmd *cobra.Command, args []string) error {
@@ -51,6 +53,10 @@ Examples:
 func init() {
 	// Define version flag
 	rootCmd.Flags().BoolP("version", "v", false, "Print version information")
+	rootCmd.Flags().String("config", "", "Config file (default $XDG_CONFIG_HOME/promtop/config.yaml)")
+	rootCmd.Flags().String("rate", "rate", "Function used to calculate rates from counters: rate (smoother) or irate (more responsive)")
+	rootCmd.Flags().StringSlice("group-by", nil, "Prometheus target labels to group nodes by, outermost first (e.g. datacenter,role)")
+	rootCmd.Flags().String("theme", "dark", "Color theme: "+strings.Join(promtop.ThemeNames(), ", ")+" or a theme defined in the config file")
 }
 
 func parseAndValidateURL(rawURL string) (*url.URL, error) {
@@ -89,6 +95,22 @@ func run(cmd *cobra.Command, args []string) {
 	log.SetOutput(os.Stderr)
 	log.Printf("Starting Promtop %s", version)
 
+	// Apply settings before any source is queried
+	// Flags override the config file, which overrides the defaults
+	// Node names like 10.0.0.1:9100 are used as alias keys so don't split keys on dots
+	v := viper.NewWithOptions(viper.KeyDelimiter("::"))
+	v.BindPFlag("rate", cmd.Flags().Lookup("rate"))
+	v.BindPFlag("theme", cmd.Flags().Lookup("theme"))
+	v.BindPFlag("group_by", cmd.Flags().Lookup("group-by"))
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := promtop.LoadConfig(v, configPath)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := promtop.SetConfig(cfg); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
 	var sources []promtop.Data
 	var sourceNames []string
 